@@ -18,7 +18,12 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance"
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
 )
 
 var (
@@ -35,52 +40,120 @@ var (
 	runtime                 = flag.String("builder-runtime", "", "runtime to use in building. Required if -buildpacks=true")
 	tag                     = flag.String("builder-tag", "latest", "builder image tag to use in building")
 	runtimeVersion          = flag.String("builder-runtime-version", "", "runtime version used when building.")
+	builderEnvs             = flag.String("builder-envs", "", "a comma separated string of additional build environment variables, as KEY=VALUE or KEY to take the value from the environment")
+	builderBuildpacks       = flag.String("builder-buildpacks", "", "a comma separated string of additional buildpacks to use in building")
+	builderRunImage         = flag.String("builder-run-image", "", "run image to use instead of the default run image of the builder")
+	builderVolumes          = flag.String("builder-volumes", "", "a comma separated string of volumes to mount into the build containers, as /host/path:/container/path[:ro]")
 	builderURL              = flag.String("builder-url", "", "builder image url used when building docker container with pack.")
+	containerRuntime        = flag.String("container-runtime", "", "container runtime used with -buildpacks, 'docker' or 'podman', detected from DOCKER_HOST, CONTAINER_HOST and the available sockets by default")
+	prebuiltImage           = flag.String("image", "", "pre-built function image to run with -buildpacks instead of building the function, which only requires -builder-target to be set")
+	cacheBuilds             = flag.Bool("cache-builds", false, "whether to reuse the image built from the same source, builder and build environment by an earlier run with -buildpacks")
+	runtimeProfile          = flag.String("runtime-profile", "", "Cloud Functions generation whose runtime environment is emulated in the container built with -buildpacks, 'gen1' or 'gen2'")
+	pullPolicy              = flag.String("pull-policy", "always", "policy for pulling the builder, run and pre-built images with -buildpacks: 'always', 'if-not-present' or 'never'")
+	keepImage               = flag.Bool("keep-image", false, "whether to keep the image built with -buildpacks after the validation, for debugging")
 	startDelay              = flag.Uint("start-delay", 1, "Seconds to wait before sending HTTP request to command process")
 	validateConcurrencyFlag = flag.Bool("validate-concurrency", false, "whether to validate concurrent requests can be handled, requires a function that sleeps for 1 second ")
+	validateInvalidFlag     = flag.Bool("validate-invalid-events", false, "whether to validate that malformed or unsupported events are rejected without invoking the function")
+	validateStartupFlag     = flag.Bool("validate-startup", false, "whether to validate that the framework run with -cmd exits with an error when the function target is missing or nonexistent or the signature type is unknown")
+	startupTargetFlag       = flag.String("startup-target-flag", "", "command line flag of the framework that sets the function target, e.g. --target, validated to take precedence over FUNCTION_TARGET with -validate-startup")
+	fuzzCount               = flag.Int("fuzz", 0, "number of randomly generated events to send in addition to the fixed test events, for event signatures")
+	fuzzSeed                = flag.Int64("fuzz-seed", 0, "seed for generating events with -fuzz, defaults to a time-based seed that is logged")
+	fuzzExportDir           = flag.String("fuzz-export-dir", "", "directory to export minimized failing generated events to as events/data test data")
+	eventsDir               = flag.String("events-dir", "", "directory of additional test events, named like the files in events/data, for event signatures")
+	replaceEvents           = flag.Bool("replace-events", false, "whether events in -events-dir replace the built-in test events instead of being added to them")
+	streamLogs              = flag.Bool("stream-logs", false, "whether to log what the Functions Framework server logs as it is written, instead of only on failure")
 	envs                    = flag.String("envs", "", "a comma separated string of additional runtime environment variables")
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reference":
+			runReference(os.Args[2:])
+			return
+		case "mutate":
+			runMutate(os.Args[2:])
+			return
+		}
+	}
 	flag.Parse()
 
 	if *useBuildpacks {
-		if *runtime == "" || *source == "" || *target == "" {
+		if *prebuiltImage != "" {
+			if *target == "" {
+				log.Fatalf("testing a pre-built image requires -builder-target to be set")
+			}
+		} else if *runtime == "" || *source == "" || *target == "" {
 			log.Fatalf("testing via buildpacks requires -builder-runtime, -builder-source, and -builder-target to be set")
 		}
 	}
 
-	if *declarativeSignature == "" {
-		*declarativeSignature = *functionSignature
+	if *validateStartupFlag && *useBuildpacks {
+		log.Fatalf("-validate-startup requires -buildpacks=false and -cmd to be set")
 	}
 
-	if *functionSignature == "legacyevent" {
-		*functionSignature = "event"
+	if *validateInvalidFlag && !*validateMapping && (*declarativeSignature == "legacyevent" || *declarativeSignature == "" && *functionSignature == "legacyevent") {
+		log.Fatalf("-validate-invalid-events requires -validate-mapping for legacy event functions, which are only sent invalid CloudEvents")
+	}
+
+	if *eventsDir != "" {
+		if err := events.UseEvents(*eventsDir, *replaceEvents); err != nil {
+			log.Fatalf("loading events from -events-dir: %v", err)
+		}
+	} else if *replaceEvents {
+		log.Fatalf("-replace-events requires -events-dir to be set")
 	}
-	// Set runtime env vars that reflect https://cloud.google.com/functions/docs/configuring/env-var
-	validationRuntimeEnv := []string{"FUNCTION_SIGNATURE_TYPE=" + *functionSignature}
-	validationRuntimeEnv = append(validationRuntimeEnv, strings.Split(*envs, ",")...)
 
-	v := newValidator(validatorParams{
-		validateMapping:      *validateMapping,
-		useBuildpacks:        *useBuildpacks,
-		runCmd:               *runCmd,
-		outputFile:           *outputFile,
-		source:               *source,
-		target:               *target,
-		runtime:              *runtime,
-		runtimeVersion:       *runtimeVersion,
-		functionSignature:    *functionSignature,
-		declarativeSignature: *declarativeSignature,
-		tag:                  *tag,
-		validateConcurrency:  *validateConcurrencyFlag,
-		envs:                 validationRuntimeEnv,
-		builderURL:           *builderURL,
+	if *fuzzSeed == 0 {
+		*fuzzSeed = time.Now().UnixNano()
+	}
+
+	v := conformance.NewValidator(conformance.ValidatorParams{
+		ValidateMapping:      *validateMapping,
+		UseBuildpacks:        *useBuildpacks,
+		RunCmd:               *runCmd,
+		StartDelay:           time.Duration(*startDelay) * time.Second,
+		OutputFile:           *outputFile,
+		Source:               *source,
+		Target:               *target,
+		Runtime:              *runtime,
+		RuntimeVersion:       *runtimeVersion,
+		FunctionSignature:    *functionSignature,
+		DeclarativeSignature: *declarativeSignature,
+		Tag:                  *tag,
+		ValidateConcurrency:  *validateConcurrencyFlag,
+		ValidateInvalid:      *validateInvalidFlag,
+		ValidateStartup:      *validateStartupFlag,
+		StartupTargetFlag:    *startupTargetFlag,
+		FuzzCount:            *fuzzCount,
+		FuzzSeed:             *fuzzSeed,
+		FuzzExportDir:        *fuzzExportDir,
+		Envs:                 splitList(*envs),
+		StreamLogs:           *streamLogs,
+		BuilderURL:           *builderURL,
+		ContainerRuntime:     *containerRuntime,
+		KeepImage:            *keepImage,
+		Image:                *prebuiltImage,
+		CacheBuilds:          *cacheBuilds,
+		PullPolicy:           *pullPolicy,
+		RuntimeProfile:       *runtimeProfile,
+		BuildEnvs:            splitList(*builderEnvs),
+		Buildpacks:           splitList(*builderBuildpacks),
+		RunImage:             *builderRunImage,
+		Volumes:              splitList(*builderVolumes),
 	})
 
-	if err := v.runValidation(); err != nil {
+	if err := v.Run(); err != nil {
 		log.Fatalf("%v", err)
 	}
 
 	log.Printf("All validation passed!")
 }
+
+// splitList splits a comma separated flag value, which may be empty.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
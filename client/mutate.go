@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance/mutation"
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+// runMutate runs the event validators against mutated output of the reference server and prints
+// the kill rate of each test case, e.g. with `client mutate -type=cloudevent`. It fails if a
+// mutation is not detected.
+func runMutate(args []string) {
+	fs := flag.NewFlagSet("mutate", flag.ExitOnError)
+	signatures := []string{"cloudevent", "legacyevent"}
+	signature := fs.String("type", "", "the declarative signature to validate (must be 'cloudevent' or 'legacyevent'), defaults to both")
+	validateMapping := fs.Bool("validate-mapping", true, "whether to also mutate events converted from the other event type")
+	eventsDir := fs.String("events-dir", "", "directory of additional test events, named like the files in events/data")
+	fs.Parse(args)
+
+	if *signature != "" {
+		signatures = []string{*signature}
+	}
+	if *eventsDir != "" {
+		if err := events.UseEvents(*eventsDir, false); err != nil {
+			log.Fatalf("loading events from -events-dir: %v", err)
+		}
+	}
+
+	survivors := 0
+	for _, s := range signatures {
+		report, err := mutation.Run(s, *validateMapping)
+		if err != nil {
+			log.Fatalf("mutation testing %s validators: %v", s, err)
+		}
+		fmt.Printf("%s validators\n%s\n", s, report)
+		survivors += report.Survivors()
+	}
+	if survivors > 0 {
+		log.Fatalf("%d mutations were not detected", survivors)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance/reference"
+)
+
+// runReference runs the reference Functions Framework server until it fails, e.g. with
+// `client reference -type=cloudevent`, so that the client can be validated against it with
+// `client -buildpacks=false -type=cloudevent -cmd="client reference -type=cloudevent"`.
+func runReference(args []string) {
+	fs := flag.NewFlagSet("reference", flag.ExitOnError)
+	signature := fs.String("type", "http", "the declarative signature of the function (must be 'http', 'typed', 'cloudevent', or 'legacyevent')")
+	port := fs.Int("port", 8080, "port to listen on")
+	outputFile := fs.String("output-file", "function_output.json", "name of the file the function writes what it receives to")
+	faults := fs.String("faults", "", fmt.Sprintf("a comma separated list of faults to inject, from %v", reference.Faults()))
+	delay := fs.Duration("delay", 0, "how long the function runs for, e.g. 1s to validate concurrency")
+	fs.Parse(args)
+
+	parsedFaults, err := reference.ParseFaults(*faults)
+	if err != nil {
+		log.Fatalf("parsing -faults: %v", err)
+	}
+	h, err := reference.NewHandler(reference.Options{
+		Signature: *signature,
+		Faults:    parsedFaults,
+		Delay:     *delay,
+	}, func(output []byte) {
+		if err := ioutil.WriteFile(*outputFile, output, 0644); err != nil {
+			log.Printf("writing output file: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	addr := fmt.Sprintf(":%d", *port)
+	log.Printf("Reference %s server listening at %s with faults %v", *signature, addr, parsedFaults)
+	log.Fatal(http.ListenAndServe(addr, h))
+}
@@ -0,0 +1,581 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	pack "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/moby/moby/api/types/container"
+)
+
+const (
+	namePrefix                = "conformance-test-func"
+	runLabel                  = "functions-framework-conformance.run"
+	workspaceDir              = "/workspace"
+	defaultBuilderURLTemplate = "us-docker.pkg.dev/serverless-runtimes/google-22-full/builder/%s:%s"
+	gcfTargetPlatform         = "gcf"
+)
+
+type buildpacksFunctionServer struct {
+	functionOutputFile string
+	source             string
+	target             string
+	funcType           string
+	runtime            string
+	runtimeVersion     string
+	tag                string
+	containerRuntime   containerRuntime
+	// containerRuntimeName selects the container runtime, which is detected if it is empty.
+	containerRuntimeName string
+	docker               dockerClient
+	// name is the name of the container, which is unique to the run.
+	name string
+	// image is the function image that is run. Unless it is pre-built or cached, it is built
+	// with the name of the run and removed in cleanup, which temporaryImage records.
+	image          string
+	temporaryImage bool
+	prebuiltImage  string
+	pullPolicy     image.PullPolicy
+	profile        *runtimeProfile
+	// profileName selects the runtime profile to emulate, none if it is empty.
+	profileName string
+	// hostOutputFile is bind mounted as the output file if the file system is read-only.
+	hostOutputFile string
+	// pullPolicyName selects the pull policy, "always" if it is empty.
+	pullPolicyName string
+	cacheBuilds    bool
+	keepImage      bool
+	ctID           string
+	cleanupOnce    sync.Once
+	cleanupErr     error
+	logs           *serverLogs
+	logStdout      io.WriteCloser
+	logStderr      io.WriteCloser
+	// logsCopied is closed once the container logs are copied, after the container stops.
+	logsCopied chan struct{}
+	exit       *exitWatcher
+	// interrupt records that the process was interrupted while the function was running.
+	interrupt  *exitWatcher
+	stopping   atomic.Bool
+	stdoutFile string
+	stderrFile string
+	builderURL string
+	// buildEnvs, buildpacks, runImage and volumes are passed to the build in addition to the
+	// defaults, see pack.BuildOptions.
+	buildEnvs  []string
+	buildpacks []string
+	runImage   string
+	volumes    []string
+	envs       []string
+	startDelay time.Duration
+}
+
+func (b *buildpacksFunctionServer) Start(stdoutFile, stderrFile, functionOutputFile string) (func(), error) {
+	b.functionOutputFile = functionOutputFile
+	b.stdoutFile = stdoutFile
+	b.stderrFile = stderrFile
+	var err error
+	b.pullPolicy, err = parsePullPolicy(b.pullPolicyName)
+	if err != nil {
+		return nil, err
+	}
+	b.profile, err = lookupRuntimeProfile(b.profileName)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := newContainerRuntime(b.containerRuntimeName)
+	if err != nil {
+		return nil, err
+	}
+	b.containerRuntime = rt
+	docker, err := newClient(rt)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s client for %s: %v", rt.name(), rt.host(), err)
+	}
+	b.docker = docker
+	log.Printf("Using container runtime %s at %s.", rt.name(), rt.host())
+
+	b.name, err = uniqueName()
+	if err != nil {
+		return nil, err
+	}
+	// The context is canceled if interrupted, to stop building and pulling images.
+	ctx, cancel := context.WithCancel(context.Background())
+	b.interrupt = newExitWatcher()
+	stopInterruptHandler := b.cleanupOnInterrupt(cancel)
+	stop := func() {
+		stopInterruptHandler()
+		cancel()
+	}
+
+	shutdown, err := b.launch(ctx, docker)
+	if err != nil {
+		stop()
+		return nil, b.interruptedOr(err)
+	}
+
+	return func() {
+		shutdown()
+		stop()
+	}, nil
+}
+
+// launch prepares the function image and runs it, cleaning up if either fails.
+func (b *buildpacksFunctionServer) launch(ctx context.Context, docker pack.DockerClient) (func(), error) {
+	if err := b.prepareImage(ctx, docker); err != nil {
+		b.cleanup()
+		return nil, fmt.Errorf("building function container: %v", err)
+	}
+
+	shutdown, err := b.run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("running function container: %v", err)
+	}
+	return shutdown, nil
+}
+
+// interruptedOr returns the error describing the interrupt if the process was interrupted, which
+// makes what was running fail, or err otherwise.
+func (b *buildpacksFunctionServer) interruptedOr(err error) error {
+	if interruptErr := b.interrupt.wait(0); interruptErr != nil {
+		return interruptErr
+	}
+	return err
+}
+
+// uniqueName returns a name for the image and the container of a run, so that concurrent runs on
+// one machine do not conflict.
+func uniqueName() (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating container name: %v", err)
+	}
+	return namePrefix + "-" + hex.EncodeToString(suffix), nil
+}
+
+// cleanupOnInterrupt removes the container and the image if the process is interrupted, e.g. with
+// Ctrl-C, after canceling what is running with cancel. The interrupt is reported as the error of
+// Start, or as the exit of the server once it runs, so that the caller fails instead of the
+// process exiting. Interrupting again exits. The returned function stops handling interrupts.
+func (b *buildpacksFunctionServer) cleanupOnInterrupt(cancel context.CancelFunc) func() {
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-interrupted:
+			signal.Stop(interrupted)
+			b.interrupted(sig, cancel)
+		case <-stop:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(interrupted)
+			close(stop)
+		})
+	}
+}
+
+// interrupted records the interrupt by sig, cancels what is running and cleans up.
+func (b *buildpacksFunctionServer) interrupted(sig os.Signal, cancel context.CancelFunc) {
+	log.Printf("Received %v, removing function container %q.", sig, b.name)
+	b.interrupt.exited(fmt.Errorf("interrupted by %v", sig))
+	cancel()
+	if err := b.cleanup(); err != nil {
+		log.Printf("Failed to clean up: %v", err)
+	}
+}
+
+func (b *buildpacksFunctionServer) OutputFile() ([]byte, error) {
+	if b.hostOutputFile != "" {
+		output, err := ioutil.ReadFile(b.hostOutputFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(output) == 0 {
+			return nil, fmt.Errorf("function did not write %s", b.functionOutputFile)
+		}
+		return output, nil
+	}
+	output, err := readContainerFile(context.Background(), b.docker, b.ctID, path.Join(workspaceDir, b.functionOutputFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy output file from the container: %v", err)
+	}
+	return output, nil
+}
+
+func (b *buildpacksFunctionServer) ClearOutputFile() error {
+	if b.hostOutputFile != "" {
+		// The mounted file cannot be removed, but empty output counts as none.
+		return os.Truncate(b.hostOutputFile, 0)
+	}
+	cmd := []string{"rm", "-f", path.Join(workspaceDir, b.functionOutputFile)}
+	if err := execInContainer(context.Background(), b.docker, b.ctID, cmd); err != nil {
+		return fmt.Errorf("failed to remove output file from the container: %v", err)
+	}
+	return nil
+}
+
+// prepareImage sets the function image to run, building it unless it is pre-built or cached.
+func (b *buildpacksFunctionServer) prepareImage(ctx context.Context, docker pack.DockerClient) error {
+	if b.prebuiltImage != "" {
+		b.image = b.prebuiltImage
+		log.Printf("Using pre-built function image %q.", b.image)
+		// A pre-built image is usually built locally, so it is only pulled if it is missing.
+		policy := b.pullPolicy
+		if policy == image.PullAlways {
+			policy = image.PullIfNotPresent
+		}
+		return ensureImage(ctx, b.docker, b.image, policy)
+	}
+
+	builder, err := b.buildpackBuilderImage()
+	if err != nil {
+		return err
+	}
+	opts := b.buildOptions(builder)
+	if err := ensureImage(ctx, b.docker, opts.Builder, b.pullPolicy); err != nil {
+		return fmt.Errorf("getting builder image: %v", err)
+	}
+	if !b.cacheBuilds {
+		b.image = b.name
+		b.temporaryImage = true
+		return b.build(ctx, docker, opts)
+	}
+
+	key, err := b.cacheKey(ctx, opts)
+	if err != nil {
+		return err
+	}
+	b.image = cachedImage(key)
+	exists, err := imageExists(ctx, b.docker, b.image)
+	if err != nil {
+		return fmt.Errorf("looking up cached image %q: %v", b.image, err)
+	}
+	if exists {
+		log.Printf("Using cached function image %q.", b.image)
+		return nil
+	}
+	log.Printf("No cached function image, building %q.", b.image)
+	return b.build(ctx, docker, opts)
+}
+
+// cacheKey returns the cache key of the image built with opts, once the builder image is available
+// locally. The run image is made available to identify it too.
+func (b *buildpacksFunctionServer) cacheKey(ctx context.Context, opts pack.BuildOptions) (string, error) {
+	builderID, err := imageID(ctx, b.docker, opts.Builder)
+	if err != nil {
+		return "", fmt.Errorf("looking up builder image %q: %v", opts.Builder, err)
+	}
+	var runImageID string
+	if opts.RunImage != "" {
+		if err := ensureImage(ctx, b.docker, opts.RunImage, b.pullPolicy); err != nil {
+			return "", fmt.Errorf("getting run image: %v", err)
+		}
+		runImageID, err = imageID(ctx, b.docker, opts.RunImage)
+		if err != nil {
+			return "", fmt.Errorf("looking up run image %q: %v", opts.RunImage, err)
+		}
+	}
+	return buildCacheKey(opts, builderID, runImageID)
+}
+
+// buildOptions returns the options for building the function with a builder, except for the
+// image name.
+func (b *buildpacksFunctionServer) buildOptions(builder string) pack.BuildOptions {
+	return pack.BuildOptions{
+		Builder:  builder,
+		AppPath:  b.source,
+		Registry: "",
+		Env:      b.buildEnv(),
+		// Additional buildpacks are chosen by the user like the builder, so they are trusted too.
+		Buildpacks:           b.buildpacks,
+		TrustExtraBuildpacks: true,
+		RunImage:             b.runImage,
+		ContainerConfig:      pack.ContainerConfig{Volumes: b.volumes},
+		TrustBuilder:         func(string) bool { return true },
+		PullPolicy:           b.pullPolicy,
+	}
+}
+
+// buildEnv returns the environment variables of the build. Variables set with buildEnvs take
+// precedence, and a variable without a value takes its value from the environment.
+func (b *buildpacksFunctionServer) buildEnv() map[string]string {
+	env := map[string]string{
+		"GOOGLE_FUNCTION_TARGET":         b.target,
+		"GOOGLE_FUNCTION_SIGNATURE_TYPE": b.funcType,
+		"GOOGLE_RUNTIME":                 b.runtime,
+		"GOOGLE_RUNTIME_VERSION":         b.runtimeVersion,
+		"X_GOOGLE_TARGET_PLATFORM":       gcfTargetPlatform,
+	}
+	for _, s := range b.buildEnvs {
+		if s == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			value = os.Getenv(key)
+		}
+		env[key] = value
+	}
+	return env
+}
+
+func (b *buildpacksFunctionServer) build(ctx context.Context, docker pack.DockerClient, opts pack.BuildOptions) error {
+	logger := logging.NewLogWithWriters(os.Stdout, os.Stderr, logging.WithVerbose())
+	packClient, err := pack.NewClient(pack.WithLogger(logger), pack.WithDockerClient(docker))
+	if err != nil {
+		return fmt.Errorf("getting pack client: %v", err)
+	}
+	opts.Image = b.image
+	opts.DockerHost = b.containerRuntime.buildHost()
+	if err := packClient.Build(ctx, opts); err != nil {
+		return fmt.Errorf("building function image: %v", err)
+	}
+
+	return nil
+}
+
+// parsePullPolicy parses a pull policy, "always", "if-not-present" or "never".
+func parsePullPolicy(name string) (image.PullPolicy, error) {
+	policy, err := image.ParsePullPolicy(name)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported pull policy %q, want 'always', 'if-not-present' or 'never'", name)
+	}
+	return policy, nil
+}
+
+var runtimeLanguageRegexp = regexp.MustCompile(`^[a-zA-Z]+`)
+
+func (b *buildpacksFunctionServer) buildpackBuilderImage() (string, error) {
+	if b.builderURL != "" {
+		return b.builderURL, nil
+	}
+	runtimeLanguage := runtimeLanguageRegexp.FindString(b.runtime)
+	if runtimeLanguage == "" {
+		return "", fmt.Errorf("invalid runtime format. Runtime should start with language followed by version. Example: go119, python311. Got %q", b.runtime)
+	}
+	return fmt.Sprintf(defaultBuilderURLTemplate, runtimeLanguage, b.tag), nil
+}
+
+func (b *buildpacksFunctionServer) run(ctx context.Context) (func(), error) {
+	// Create logs output files.
+	var err error
+	b.logStdout, err = b.logs.create(b.stdoutFile, "stdout")
+	if err != nil {
+		b.cleanup()
+		return nil, err
+	}
+
+	b.logStderr, err = b.logs.create(b.stderrFile, "stderr")
+	if err != nil {
+		b.cleanup()
+		return nil, err
+	}
+
+	config, hostConfig := b.containerConfig()
+	if b.profile != nil {
+		if err := b.emulate(ctx, config, hostConfig); err != nil {
+			b.cleanup()
+			return nil, fmt.Errorf("emulating %s runtime: %v", b.profile.name, err)
+		}
+	}
+	b.ctID, err = createContainer(ctx, b.docker, b.name, config, hostConfig)
+	if err != nil {
+		b.cleanup()
+		return nil, err
+	}
+	logsDone, err := streamLogs(ctx, b.docker, b.ctID, b.logStdout, b.logStderr)
+	if err != nil {
+		b.cleanup()
+		return nil, fmt.Errorf("getting container logs: %v", err)
+	}
+	b.logsCopied = make(chan struct{})
+	go func() {
+		defer close(b.logsCopied)
+		if err := <-logsDone; err != nil {
+			log.Printf("Failed to copy container logs: %v", err)
+		}
+	}()
+	b.exit = newExitWatcher()
+	go b.watchExit(ctx)
+
+	// Give it some time to do its setup, unless it exits.
+	if err := b.exit.wait(b.startDelay); err != nil {
+		b.cleanup()
+		return nil, err
+	}
+	if err := checkRunning(ctx, b.docker, b.ctID); err != nil {
+		// The container may have stopped before its exit was reported.
+		if exitErr := b.exit.wait(exitWait); exitErr != nil {
+			err = exitErr
+		}
+		b.cleanup()
+		return nil, err
+	}
+	log.Printf("Framework container %q (%s) started.", b.name, b.ctID)
+
+	return func() {
+		if err := b.cleanup(); err != nil {
+			log.Printf("Failed to clean up: %v", err)
+		}
+		log.Printf("Wrote logs to %v and %v.", b.stdoutFile, b.stderrFile)
+		log.Print("Framework server shut down.")
+	}, nil
+}
+
+// watchExit waits for the container to stop running, and reports it as exited unless it is
+// stopped in cleanup.
+func (b *buildpacksFunctionServer) watchExit(ctx context.Context) {
+	code, err := waitContainer(ctx, b.docker, b.ctID)
+	if b.stopping.Load() {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to wait for container %q: %v", b.name, err)
+		return
+	}
+	// The last logs are the most useful to find why it exited.
+	select {
+	case <-b.logsCopied:
+	case <-time.After(exitWait):
+	}
+	oom, err := oomKilled(ctx, b.docker, b.ctID)
+	if err != nil {
+		log.Printf("Failed to inspect container %q: %v", b.name, err)
+	}
+	b.exit.exited(&serverExitError{
+		status:    fmt.Sprintf("exit code %d", code),
+		code:      int(code),
+		oomKilled: oom,
+		step:      b.logs.currentStep(),
+		lastLogs:  b.logs.lastLines(),
+	})
+}
+
+func (b *buildpacksFunctionServer) exitError(d time.Duration) error {
+	if err := b.interrupt.wait(0); err != nil {
+		return err
+	}
+	return b.exit.wait(d)
+}
+
+func (b *buildpacksFunctionServer) containerConfig() (*container.Config, *container.HostConfig) {
+	env := []string{
+		// TODO: figure out why these aren't getting set in the buildpack.
+		"FUNCTION_TARGET=" + b.target,
+		"FUNCTION_SIGNATURE_TYPE=" + b.funcType,
+	}
+	for _, s := range b.envs {
+		if s != "" {
+			env = append(env, s)
+		}
+	}
+	config := &container.Config{
+		Image:  b.image,
+		Env:    env,
+		Labels: map[string]string{runLabel: b.name},
+	}
+	hostConfig := &container.HostConfig{}
+	b.containerRuntime.network(config, hostConfig)
+	return config, hostConfig
+}
+
+// emulate applies the runtime profile to the container configuration.
+func (b *buildpacksFunctionServer) emulate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig) error {
+	user, err := imageUser(ctx, b.docker, b.image)
+	if err != nil {
+		return fmt.Errorf("getting user of image %q: %v", b.image, err)
+	}
+	f, err := ioutil.TempFile("", "function_output")
+	if err != nil {
+		return err
+	}
+	b.hostOutputFile = f.Name()
+	f.Close()
+	// The function may not run as the user running the validation.
+	if err := os.Chmod(b.hostOutputFile, 0666); err != nil {
+		return err
+	}
+	b.profile.apply(config, hostConfig, user, b.hostOutputFile, path.Join(workspaceDir, b.functionOutputFile))
+	return nil
+}
+
+// cleanup removes the container, waits for its logs to be written, closes the log files and
+// removes an image built for the run unless it is kept. It only cleans up once, so it is safe to
+// call again, e.g. when interrupted during shutdown. It does not use the context of the run, which
+// is canceled when interrupted.
+func (b *buildpacksFunctionServer) cleanup() error {
+	b.cleanupOnce.Do(func() {
+		ctx := context.Background()
+		b.stopping.Store(true)
+		var errs []string
+		// The container may have been created without its ID being known if interrupted.
+		ct := b.ctID
+		if ct == "" {
+			ct = b.name
+		}
+		if ct != "" {
+			if err := removeContainer(ctx, b.docker, ct); err != nil {
+				errs = append(errs, fmt.Sprintf("removing container %q: %v", b.name, err))
+			}
+		}
+		if b.logsCopied != nil {
+			<-b.logsCopied
+		}
+		if b.logStdout != nil {
+			b.logStdout.Close()
+		}
+		if b.logStderr != nil {
+			b.logStderr.Close()
+		}
+		if b.hostOutputFile != "" {
+			os.Remove(b.hostOutputFile)
+		}
+		switch {
+		case !b.temporaryImage:
+		case b.keepImage:
+			log.Printf("Kept function image %q.", b.image)
+		default:
+			if err := removeImage(ctx, b.docker, b.image); err != nil {
+				errs = append(errs, fmt.Sprintf("removing image %q: %v", b.image, err))
+			}
+		}
+		if len(errs) > 0 {
+			b.cleanupErr = fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+	})
+	return b.cleanupErr
+}
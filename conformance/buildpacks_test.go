@@ -0,0 +1,102 @@
+package conformance
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBuildpackBuilderImage(t *testing.T) {
+	testCases := []struct {
+		name           string
+		runtime        string
+		tag            string
+		wantError      bool
+		wantBuilderURL string
+	}{
+		{
+			name:           "Extracts go from go119",
+			runtime:        "go119",
+			tag:            "1.2.3",
+			wantBuilderURL: fmt.Sprintf(defaultBuilderURLTemplate, "go", "1.2.3"),
+		},
+		{
+			name:      "Fails with incorrect runtime format",
+			runtime:   "11go",
+			tag:       "latest",
+			wantError: true,
+		},
+		{
+			name:           "Extracts php from php82",
+			runtime:        "php82",
+			tag:            "latest",
+			wantBuilderURL: fmt.Sprintf(defaultBuilderURLTemplate, "php", "latest"),
+		},
+		{
+			name:           "Extracts nodejs from nodejs18",
+			runtime:        "nodejs18",
+			tag:            "18",
+			wantBuilderURL: fmt.Sprintf(defaultBuilderURLTemplate, "nodejs", "18"),
+		},
+		{
+			name:           "Extracts dotnet from dotnet6",
+			runtime:        "dotnet6",
+			tag:            "123",
+			wantBuilderURL: fmt.Sprintf(defaultBuilderURLTemplate, "dotnet", "123"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &buildpacksFunctionServer{
+				runtime: tc.runtime,
+				tag:     tc.tag,
+			}
+
+			builderImage, err := b.buildpackBuilderImage()
+
+			if err != nil && !tc.wantError {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+
+			if builderImage != tc.wantBuilderURL {
+				t.Errorf("buildpackBuilderImage() = %q, want %q", builderImage, tc.wantBuilderURL)
+			}
+		})
+	}
+}
+
+func TestBuildpackBuildOptions(t *testing.T) {
+	t.Setenv("NPM_TOKEN", "secret")
+	b := &buildpacksFunctionServer{
+		source:     "testdata",
+		target:     "Func",
+		funcType:   "http",
+		runtime:    "nodejs20",
+		buildEnvs:  []string{"GOOGLE_NODE_RUN_SCRIPTS=", "GOOGLE_RUNTIME_VERSION=20.1.0", "NPM_TOKEN", ""},
+		buildpacks: []string{"gcr.io/example/buildpack"},
+		runImage:   "gcr.io/example/run",
+		volumes:    []string{"/tmp/cache:/cache:ro"},
+	}
+
+	opts := b.buildOptions("builder")
+
+	wantEnv := map[string]string{
+		"GOOGLE_FUNCTION_TARGET":         "Func",
+		"GOOGLE_FUNCTION_SIGNATURE_TYPE": "http",
+		"GOOGLE_RUNTIME":                 "nodejs20",
+		"GOOGLE_RUNTIME_VERSION":         "20.1.0",
+		"GOOGLE_NODE_RUN_SCRIPTS":        "",
+		"NPM_TOKEN":                      "secret",
+		"X_GOOGLE_TARGET_PLATFORM":       gcfTargetPlatform,
+	}
+	if !reflect.DeepEqual(opts.Env, wantEnv) {
+		t.Errorf("buildOptions() env = %v, want %v", opts.Env, wantEnv)
+	}
+	if opts.Builder != "builder" || opts.AppPath != "testdata" || opts.RunImage != "gcr.io/example/run" {
+		t.Errorf("buildOptions() builder, source and run image = %q, %q and %q, want %q, %q and %q", opts.Builder, opts.AppPath, opts.RunImage, "builder", "testdata", "gcr.io/example/run")
+	}
+	if !reflect.DeepEqual(opts.Buildpacks, b.buildpacks) || !reflect.DeepEqual(opts.ContainerConfig.Volumes, b.volumes) {
+		t.Errorf("buildOptions() buildpacks and volumes = %v and %v, want %v and %v", opts.Buildpacks, opts.ContainerConfig.Volumes, b.buildpacks, b.volumes)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	pack "github.com/buildpacks/pack/pkg/client"
+)
+
+// cacheRepository is the repository of cached function images, which are tagged with their
+// cache key.
+const cacheRepository = namePrefix + "-cache"
+
+// buildCacheKey returns a key identifying the image built with the given options, from their
+// function source directory, builder and the other options that change the image. The builder and
+// the run image, if set, are identified by the IDs of their local images, builderID and
+// runImageID, not by their references, so that a tag pushed again does not reuse a stale image.
+func buildCacheKey(opts pack.BuildOptions, builderID, runImageID string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "builder %q\n", builderID)
+	fmt.Fprintf(h, "run image %q\n", runImageID)
+	for _, bp := range opts.Buildpacks {
+		fmt.Fprintf(h, "buildpack %q\n", bp)
+	}
+	for _, v := range opts.ContainerConfig.Volumes {
+		fmt.Fprintf(h, "volume %q\n", v)
+	}
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "env %q=%q\n", k, opts.Env[k])
+	}
+	if err := hashTree(h, opts.AppPath); err != nil {
+		return "", fmt.Errorf("hashing function source %s: %v", opts.AppPath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashTree writes the paths, modes and contents of the files in a directory to a hash, in lexical
+// order.
+func hashTree(h hash.Hash, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "file %q %v\n", filepath.ToSlash(rel), info.Mode())
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link %q\n", target)
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			fmt.Fprintf(h, "size %d\n", info.Size())
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// cachedImage returns the reference of the cached image with the given cache key.
+func cachedImage(key string) string {
+	return cacheRepository + ":" + key[:32]
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pack "github.com/buildpacks/pack/pkg/client"
+)
+
+// cacheKeyInput are the arguments of buildCacheKey.
+type cacheKeyInput struct {
+	opts       pack.BuildOptions
+	builderID  string
+	runImageID string
+}
+
+func TestBuildCacheKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		// change changes the inputs of the key or the source directory.
+		change   func(in *cacheKeyInput) error
+		wantSame bool
+	}{
+		{
+			name:     "unchanged",
+			change:   func(in *cacheKeyInput) error { return nil },
+			wantSame: true,
+		},
+		{
+			name: "builder",
+			change: func(in *cacheKeyInput) error {
+				in.opts.Builder = "other-builder"
+				in.builderID = "sha256:other-builder"
+				return nil
+			},
+		},
+		{
+			name: "builder tag pushed again",
+			change: func(in *cacheKeyInput) error {
+				in.builderID = "sha256:new-builder"
+				return nil
+			},
+		},
+		{
+			name: "env",
+			change: func(in *cacheKeyInput) error {
+				in.opts.Env = map[string]string{"GOOGLE_FUNCTION_TARGET": "Other"}
+				return nil
+			},
+		},
+		{
+			name: "buildpacks",
+			change: func(in *cacheKeyInput) error {
+				in.opts.Buildpacks = []string{"example/buildpack"}
+				return nil
+			},
+		},
+		{
+			name: "run image",
+			change: func(in *cacheKeyInput) error {
+				in.opts.RunImage = "example.com/run"
+				in.runImageID = "sha256:run"
+				return nil
+			},
+		},
+		{
+			name: "volumes",
+			change: func(in *cacheKeyInput) error {
+				in.opts.ContainerConfig.Volumes = []string{"/tmp:/data"}
+				return nil
+			},
+		},
+		{
+			name: "file contents",
+			change: func(in *cacheKeyInput) error {
+				return ioutil.WriteFile(filepath.Join(in.opts.AppPath, "main.go"), []byte("package other"), 0644)
+			},
+		},
+		{
+			name: "new file",
+			change: func(in *cacheKeyInput) error {
+				return ioutil.WriteFile(filepath.Join(in.opts.AppPath, "sub", "go.mod"), nil, 0644)
+			},
+		},
+		{
+			name: "renamed file",
+			change: func(in *cacheKeyInput) error {
+				return os.Rename(filepath.Join(in.opts.AppPath, "main.go"), filepath.Join(in.opts.AppPath, "function.go"))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package function"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			in := cacheKeyInput{
+				opts: pack.BuildOptions{
+					Builder: "builder",
+					AppPath: dir,
+					Env:     map[string]string{"GOOGLE_FUNCTION_TARGET": "Func"},
+				},
+				builderID: "sha256:builder",
+			}
+			before, err := buildCacheKey(in.opts, in.builderID, in.runImageID)
+			if err != nil {
+				t.Fatalf("buildCacheKey: %v", err)
+			}
+			if err := tc.change(&in); err != nil {
+				t.Fatal(err)
+			}
+			after, err := buildCacheKey(in.opts, in.builderID, in.runImageID)
+			if err != nil {
+				t.Fatalf("buildCacheKey: %v", err)
+			}
+			if same := before == after; same != tc.wantSame {
+				t.Errorf("buildCacheKey() = %s, then %s, want same key %v", before, after, tc.wantSame)
+			}
+		})
+	}
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package conformance
 
 import (
 	"fmt"
@@ -29,12 +29,12 @@ func timeExecution(fn func() error) (time.Duration, error) {
 	return time.Since(start), err
 }
 
-// validateConcurrency validates a server can handle concurrent requests by
+// ValidateConcurrency validates a server can handle concurrent requests by
 // valdating that the response time for a single request does not increase
 // linearly with n concurrent requests, given a function that:
 // 1. Is not CPU-bound (e.g. sleeps)
 // 2. Executes for at least 1s to ensure non-trivial measurement differences
-func validateConcurrency(url string, functionType string) error {
+func ValidateConcurrency(url string, functionType string) error {
 	log.Printf("%s validation with concurrent requests...", functionType)
 	var sendFn func() error
 	switch functionType {
@@ -0,0 +1,186 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformancetest runs the conformance validations as Go subtests, so that a Functions
+// Framework can run them with `go test`:
+//
+//	func TestConformance(t *testing.T) {
+//		conformancetest.Run(t, conformance.ValidatorParams{
+//			RunCmd:            "go run ./testdata/cloudevent",
+//			FunctionSignature: "cloudevent",
+//			StartDelay:        time.Second,
+//		})
+//	}
+//
+// Each event test case is a subtest, so a single case can be run with e.g.
+// `go test -run 'TestConformance/cloud_event/storage'`.
+package conformancetest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance"
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+// Run starts the function server described by params and runs the validations that apply to it
+// against the URL of the server, see conformance.Validator.URL.
+func Run(t *testing.T, params conformance.ValidatorParams) {
+	t.Helper()
+	RunURL(t, params, "")
+}
+
+// RunURL starts the function server described by params and runs the validations that apply to it
+// against url, or the URL of the server if url is empty. It is useful for servers that do not
+// listen on the default port.
+func RunURL(t *testing.T, params conformance.ValidatorParams, url string) {
+	t.Helper()
+	v := conformance.NewValidator(params)
+	// The startup cases run their own servers, so they run before the validated server starts.
+	if names := v.StartupCaseNames(); len(names) > 0 {
+		t.Run("startup", func(t *testing.T) {
+			for _, name := range names {
+				name := name
+				t.Run(name, func(t *testing.T) {
+					vi, err := v.ValidateStartupCase(name)
+					if err != nil {
+						t.Fatal(err)
+					}
+					report(t, vi)
+				})
+			}
+		})
+	}
+
+	shutdown, err := v.Start()
+	if err != nil {
+		t.Fatalf("starting function server: %v", err)
+	}
+	t.Cleanup(shutdown)
+	if url == "" {
+		url = v.URL()
+	}
+
+	if params.ValidateConcurrency {
+		t.Run("concurrency", func(t *testing.T) {
+			if err := conformance.ValidateConcurrency(url, v.DeclarativeSignature()); err != nil {
+				t.Error(err)
+			}
+		})
+		return
+	}
+
+	switch v.DeclarativeSignature() {
+	case "http":
+		t.Run("http", func(t *testing.T) {
+			if err := v.ValidateHTTP(url); err != nil {
+				t.Error(err)
+			}
+		})
+	case "typed":
+		t.Run("typed", func(t *testing.T) {
+			if err := v.ValidateTyped(url); err != nil {
+				t.Error(err)
+			}
+		})
+	case "cloudevent", "legacyevent":
+		for _, c := range v.EventConversions() {
+			c := c
+			t.Run(c.String(), func(t *testing.T) {
+				runEvents(t, v, url, c)
+			})
+		}
+		if params.FuzzCount > 0 {
+			for _, c := range v.EventConversions() {
+				c := c
+				t.Run("fuzz "+c.String(), func(t *testing.T) {
+					vis, err := v.ValidateFuzz(url, c)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, vi := range vis {
+						report(t, vi)
+					}
+				})
+			}
+		}
+		if params.ValidateInvalid && len(v.InvalidEventTypes()) == 0 {
+			t.Run("invalid", func(t *testing.T) {
+				t.Skip("legacy event functions are only sent invalid CloudEvents, which requires ValidateMapping")
+			})
+		}
+		for _, it := range v.InvalidEventTypes() {
+			it := it
+			t.Run("invalid "+it.String(), func(t *testing.T) {
+				for _, name := range events.InvalidEventNames(it) {
+					name := name
+					t.Run(name, func(t *testing.T) {
+						vi, err := v.ValidateInvalidEvent(url, name, it)
+						if err != nil {
+							t.Fatal(err)
+						}
+						report(t, vi)
+					})
+				}
+			})
+		}
+	default:
+		t.Fatalf("unsupported declarative signature %q, want one of 'http', 'cloudevent', 'legacyevent', or 'typed'", v.DeclarativeSignature())
+	}
+}
+
+// runEvents runs each event test case of a conversion as a subtest. The subtests are run
+// sequentially, as the function server writes every event it receives to the same output file.
+func runEvents(t *testing.T, v *conformance.Validator, url string, c conformance.EventConversion) {
+	names, err := events.EventNames(c.Input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			vis, err := v.ValidateEvent(url, name, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, vi := range vis {
+				report(t, vi)
+			}
+		})
+	}
+}
+
+// report reports a validation result to t. Failures of optional test cases are only logged.
+func report(t *testing.T, vi *events.ValidationInfo) {
+	t.Helper()
+	if vi.Errs == nil {
+		if vi.SkippedReason != "" {
+			t.Skipf("%s: %s", vi.Name, vi.SkippedReason)
+		}
+		return
+	}
+	if vi.Metadata.Description != "" {
+		t.Logf("%s: %s", vi.Name, vi.Metadata.Description)
+	}
+	if vi.Metadata.Spec != "" {
+		t.Logf("see %s", vi.Metadata.Spec)
+	}
+	for _, err := range vi.Errs {
+		if vi.Metadata.Optional {
+			t.Logf("%s (optional): %v", vi.Name, err)
+			continue
+		}
+		t.Errorf("%s: %v", vi.Name, err)
+	}
+}
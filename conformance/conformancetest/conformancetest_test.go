@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformancetest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// fakeServer is a function server whose function is served by an httptest server.
+type fakeServer struct {
+	outputFile string
+}
+
+func (s *fakeServer) Start(_, _, _ string) (func(), error) {
+	return nil, nil
+}
+
+func (s *fakeServer) OutputFile() ([]byte, error) {
+	return ioutil.ReadFile(s.outputFile)
+}
+
+func (s *fakeServer) ClearOutputFile() error {
+	if err := os.Remove(s.outputFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func TestRunURL(t *testing.T) {
+	testCases := []struct {
+		signature string
+		handler   func(outputFile string) http.HandlerFunc
+	}{
+		{
+			signature: "http",
+			handler: func(outputFile string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					body, err := ioutil.ReadAll(r.Body)
+					if err == nil {
+						err = ioutil.WriteFile(outputFile, body, 0644)
+					}
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+					}
+				}
+			},
+		},
+		{
+			signature: "cloudevent",
+			handler: func(outputFile string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					ce, err := cloudevents.NewEventFromHTTPRequest(r)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					data, err := json.Marshal(ce)
+					if err == nil {
+						err = ioutil.WriteFile(outputFile, data, 0644)
+					}
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.signature, func(t *testing.T) {
+			fs := &fakeServer{outputFile: filepath.Join(t.TempDir(), "function_output.json")}
+			srv := httptest.NewServer(tc.handler(fs.outputFile))
+			defer srv.Close()
+
+			RunURL(t, conformance.ValidatorParams{
+				FunctionServer:    fs,
+				FunctionSignature: tc.signature,
+			}, srv.URL)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		signature string
+		handler   func(output func([]byte)) http.Handler
+	}{
+		{
+			signature: "http",
+			handler: func(output func([]byte)) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, err := ioutil.ReadAll(r.Body)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					output(body)
+				})
+			},
+		},
+		{
+			signature: "cloudevent",
+			handler: func(output func([]byte)) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					ce, err := cloudevents.NewEventFromHTTPRequest(r)
+					if err == nil {
+						err = ce.Validate()
+					}
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					data, err := json.Marshal(ce)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					output(data)
+				})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.signature, func(t *testing.T) {
+			Run(t, conformance.ValidatorParams{
+				FunctionServer:    conformance.NewHandlerServer(tc.handler),
+				FunctionSignature: tc.signature,
+				ValidateInvalid:   true,
+			})
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/buildpacks/pack/pkg/image"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// dockerClient is the subset of the Docker Engine API client used to run function containers.
+type dockerClient interface {
+	ImagePull(ctx context.Context, ref string, options client.ImagePullOptions) (client.ImagePullResponse, error)
+	ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (client.ImageInspectResult, error)
+	ImageRemove(ctx context.Context, imageID string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error)
+	ContainerCreate(ctx context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error)
+	ContainerStart(ctx context.Context, containerID string, options client.ContainerStartOptions) (client.ContainerStartResult, error)
+	ContainerInspect(ctx context.Context, containerID string, options client.ContainerInspectOptions) (client.ContainerInspectResult, error)
+	ContainerLogs(ctx context.Context, containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error)
+	ContainerRemove(ctx context.Context, containerID string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error)
+	ContainerWait(ctx context.Context, containerID string, options client.ContainerWaitOptions) client.ContainerWaitResult
+	CopyFromContainer(ctx context.Context, containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error)
+	ExecCreate(ctx context.Context, containerID string, options client.ExecCreateOptions) (client.ExecCreateResult, error)
+	ExecAttach(ctx context.Context, execID string, options client.ExecAttachOptions) (client.ExecAttachResult, error)
+	ExecInspect(ctx context.Context, execID string, options client.ExecInspectOptions) (client.ExecInspectResult, error)
+}
+
+// pullImage pulls an image and waits for the pull to complete.
+func pullImage(ctx context.Context, docker dockerClient, ref string) error {
+	resp, err := docker.ImagePull(ctx, ref, client.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	return resp.Wait(ctx)
+}
+
+// ensureImage makes an image available locally according to a pull policy: it is pulled always,
+// only if it is not present, or never.
+func ensureImage(ctx context.Context, docker dockerClient, ref string, policy image.PullPolicy) error {
+	if policy != image.PullAlways {
+		exists, err := imageExists(ctx, docker, ref)
+		if err != nil {
+			return fmt.Errorf("looking up image %q: %v", ref, err)
+		}
+		switch {
+		case exists:
+			return nil
+		case policy == image.PullNever:
+			return fmt.Errorf("image %q is not available locally and the pull policy is %q, load it with 'docker load' or use another pull policy", ref, policy)
+		}
+	}
+	if err := pullImage(ctx, docker, ref); err != nil {
+		return fmt.Errorf("failed to pull image %s: %v", ref, err)
+	}
+	return nil
+}
+
+// imageExists returns whether an image is available locally.
+func imageExists(ctx context.Context, docker dockerClient, ref string) (bool, error) {
+	if _, err := docker.ImageInspect(ctx, ref); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// imageID returns the ID of a local image.
+func imageID(ctx context.Context, docker dockerClient, ref string) (string, error) {
+	res, err := docker.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
+
+// imageUser returns the user an image runs as, which is empty for root.
+func imageUser(ctx context.Context, docker dockerClient, ref string) (string, error) {
+	res, err := docker.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if res.Config == nil {
+		return "", nil
+	}
+	return res.Config.User, nil
+}
+
+// removeImage removes an image. It is not an error if the image does not exist.
+func removeImage(ctx context.Context, docker dockerClient, ref string) error {
+	_, err := docker.ImageRemove(ctx, ref, client.ImageRemoveOptions{Force: true, PruneChildren: true})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// removeContainer kills and removes a container. It is not an error if the container does not
+// exist.
+func removeContainer(ctx context.Context, docker dockerClient, nameOrID string) error {
+	_, err := docker.ContainerRemove(ctx, nameOrID, client.ContainerRemoveOptions{Force: true})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// streamLogs copies the stdout and stderr of a container to the given writers until the container
+// is removed. The returned channel receives the result of copying once it is done.
+func streamLogs(ctx context.Context, docker dockerClient, containerID string, stdout, stderr io.Writer) (<-chan error, error) {
+	logs, err := docker.ContainerLogs(ctx, containerID, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer logs.Close()
+		_, err := stdcopy.StdCopy(stdout, stderr, logs)
+		done <- err
+	}()
+	return done, nil
+}
+
+// checkRunning returns an error if a container is not running, e.g. because the server in it
+// exited during startup.
+func checkRunning(ctx context.Context, docker dockerClient, containerID string) error {
+	res, err := docker.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return err
+	}
+	if state := res.Container.State; state != nil && !state.Running {
+		return fmt.Errorf("container %q is %s with exit code %d", containerID, state.Status, state.ExitCode)
+	}
+	return nil
+}
+
+// waitContainer waits for a container to stop running and returns its exit code.
+func waitContainer(ctx context.Context, docker dockerClient, containerID string) (int64, error) {
+	res := docker.ContainerWait(ctx, containerID, client.ContainerWaitOptions{Condition: container.WaitConditionNotRunning})
+	select {
+	case resp := <-res.Result:
+		return resp.StatusCode, nil
+	case err := <-res.Error:
+		return 0, err
+	}
+}
+
+// oomKilled returns whether a container that stopped running was killed because it ran out of
+// memory.
+func oomKilled(ctx context.Context, docker dockerClient, containerID string) (bool, error) {
+	res, err := docker.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return false, err
+	}
+	return res.Container.State != nil && res.Container.State.OOMKilled, nil
+}
+
+// readContainerFile returns the contents of a file in a container.
+func readContainerFile(ctx context.Context, docker dockerClient, containerID, path string) ([]byte, error) {
+	res, err := docker.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{SourcePath: path})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Content.Close()
+
+	// The file is sent as a tar archive containing only the file.
+	tr := tar.NewReader(res.Content)
+	if _, err := tr.Next(); err != nil {
+		return nil, fmt.Errorf("reading archive of %q: %v", path, err)
+	}
+	return ioutil.ReadAll(tr)
+}
+
+// execInContainer runs a command in a container and waits for it to exit successfully.
+func execInContainer(ctx context.Context, docker dockerClient, containerID string, cmd []string) error {
+	exec, err := docker.ExecCreate(ctx, containerID, client.ExecCreateOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+	attach, err := docker.ExecAttach(ctx, exec.ID, client.ExecAttachOptions{})
+	if err != nil {
+		return err
+	}
+	// Reading the output until it ends waits for the command to exit.
+	_, err = io.Copy(ioutil.Discard, attach.Reader)
+	attach.Close()
+	if err != nil {
+		return fmt.Errorf("reading output of %v: %v", cmd, err)
+	}
+	res, err := docker.ExecInspect(ctx, exec.ID, client.ExecInspectOptions{})
+	if err != nil {
+		return err
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("%v exited with code %d", cmd, res.ExitCode)
+	}
+	return nil
+}
+
+// createContainer creates and starts a container.
+func createContainer(ctx context.Context, docker dockerClient, name string, config *container.Config, hostConfig *container.HostConfig) (string, error) {
+	res, err := docker.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Name:       name,
+		Config:     config,
+		HostConfig: hostConfig,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating container %q: %v", name, err)
+	}
+	if _, err := docker.ContainerStart(ctx, res.ID, client.ContainerStartOptions{}); err != nil {
+		return "", fmt.Errorf("starting container %q: %v", name, err)
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,623 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/pack/pkg/image"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	imagetypes "github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+)
+
+// fakeDocker is a Docker Engine API client that runs a single fake container.
+type fakeDocker struct {
+	// exitCode is the exit code of the container; it keeps running if 0.
+	exitCode  int
+	oomKilled bool
+	stdout    string
+	stderr    string
+	// stopped is closed when a running container is removed.
+	stopped chan struct{}
+	// pulling is closed when a pull starts if set, which then blocks until it is canceled.
+	pulling chan struct{}
+
+	created *client.ContainerCreateOptions
+	id      string
+	files   map[string][]byte
+	// images are the images available locally.
+	images  map[string]bool
+	execs   map[string][]string
+	removed []string
+	pulled  []string
+	// removedImages are the images removed with ImageRemove.
+	removedImages []string
+}
+
+func newFakeDocker() *fakeDocker {
+	return &fakeDocker{
+		files:   map[string][]byte{},
+		images:  map[string]bool{},
+		execs:   map[string][]string{},
+		stopped: make(chan struct{}),
+	}
+}
+
+func (d *fakeDocker) ImagePull(ctx context.Context, ref string, options client.ImagePullOptions) (client.ImagePullResponse, error) {
+	if d.pulling != nil {
+		close(d.pulling)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	d.pulled = append(d.pulled, ref)
+	d.images[ref] = true
+	return fakePull{}, nil
+}
+
+// fakePull is a completed image pull.
+type fakePull struct {
+	client.ImagePullResponse
+}
+
+func (fakePull) Close() error {
+	return nil
+}
+
+func (fakePull) Wait(ctx context.Context) error {
+	return nil
+}
+
+func (d *fakeDocker) ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	if !d.images[imageID] {
+		return client.ImageInspectResult{}, fmt.Errorf("image %q: %w", imageID, cerrdefs.ErrNotFound)
+	}
+	return client.ImageInspectResult{InspectResponse: imagetypes.InspectResponse{ID: "sha256:" + imageID}}, nil
+}
+
+func (d *fakeDocker) ImageRemove(ctx context.Context, imageID string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	if err := ctx.Err(); err != nil {
+		return client.ImageRemoveResult{}, err
+	}
+	d.removedImages = append(d.removedImages, imageID)
+	return client.ImageRemoveResult{}, nil
+}
+
+func (d *fakeDocker) ContainerCreate(ctx context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	d.created = &options
+	d.id = "id-" + options.Name
+	return client.ContainerCreateResult{ID: d.id}, nil
+}
+
+func (d *fakeDocker) ContainerStart(ctx context.Context, containerID string, options client.ContainerStartOptions) (client.ContainerStartResult, error) {
+	return client.ContainerStartResult{}, d.check(containerID)
+}
+
+func (d *fakeDocker) ContainerInspect(ctx context.Context, containerID string, options client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
+	if err := d.check(containerID); err != nil {
+		return client.ContainerInspectResult{}, err
+	}
+	state := &container.State{Status: container.StateRunning, Running: true}
+	if d.exitCode != 0 {
+		state = &container.State{Status: container.StateExited, ExitCode: d.exitCode, OOMKilled: d.oomKilled}
+	}
+	return client.ContainerInspectResult{Container: container.InspectResponse{State: state}}, nil
+}
+
+func (d *fakeDocker) ContainerLogs(ctx context.Context, containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+	if err := d.check(containerID); err != nil {
+		return nil, err
+	}
+	var logs bytes.Buffer
+	writeLogFrame(&logs, stdcopy.Stdout, d.stdout)
+	writeLogFrame(&logs, stdcopy.Stderr, d.stderr)
+	return ioutil.NopCloser(&logs), nil
+}
+
+// writeLogFrame writes a frame of a multiplexed log stream, which has an 8 byte header holding the
+// stream and the size of the frame.
+func writeLogFrame(w *bytes.Buffer, stream stdcopy.StdType, s string) {
+	header := make([]byte, 8)
+	header[0] = byte(stream)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(s)))
+	w.Write(header)
+	w.WriteString(s)
+}
+
+func (d *fakeDocker) ContainerRemove(ctx context.Context, containerID string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	if err := ctx.Err(); err != nil {
+		return client.ContainerRemoveResult{}, err
+	}
+	if err := d.check(containerID); err != nil {
+		return client.ContainerRemoveResult{}, err
+	}
+	d.removed = append(d.removed, containerID)
+	d.id = ""
+	close(d.stopped)
+	return client.ContainerRemoveResult{}, nil
+}
+
+func (d *fakeDocker) ContainerWait(ctx context.Context, containerID string, options client.ContainerWaitOptions) client.ContainerWaitResult {
+	result := make(chan container.WaitResponse, 1)
+	if d.exitCode != 0 {
+		result <- container.WaitResponse{StatusCode: int64(d.exitCode)}
+		return client.ContainerWaitResult{Result: result}
+	}
+	go func() {
+		<-d.stopped
+		result <- container.WaitResponse{StatusCode: 137}
+	}()
+	return client.ContainerWaitResult{Result: result}
+}
+
+func (d *fakeDocker) CopyFromContainer(ctx context.Context, containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error) {
+	if err := d.check(containerID); err != nil {
+		return client.CopyFromContainerResult{}, err
+	}
+	contents, ok := d.files[options.SourcePath]
+	if !ok {
+		return client.CopyFromContainerResult{}, cerrdefs.ErrNotFound
+	}
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: filepath.Base(options.SourcePath), Mode: 0644, Size: int64(len(contents))})
+	tw.Write(contents)
+	tw.Close()
+	return client.CopyFromContainerResult{Content: ioutil.NopCloser(&archive)}, nil
+}
+
+func (d *fakeDocker) ExecCreate(ctx context.Context, containerID string, options client.ExecCreateOptions) (client.ExecCreateResult, error) {
+	if err := d.check(containerID); err != nil {
+		return client.ExecCreateResult{}, err
+	}
+	id := fmt.Sprintf("exec-%d", len(d.execs))
+	d.execs[id] = options.Cmd
+	return client.ExecCreateResult{ID: id}, nil
+}
+
+func (d *fakeDocker) ExecAttach(ctx context.Context, execID string, options client.ExecAttachOptions) (client.ExecAttachResult, error) {
+	cmd := d.execs[execID]
+	if len(cmd) == 3 && cmd[0] == "rm" && cmd[1] == "-f" {
+		delete(d.files, cmd[2])
+	}
+	conn, server := net.Pipe()
+	server.Close()
+	return client.ExecAttachResult{HijackedResponse: client.NewHijackedResponse(conn, "")}, nil
+}
+
+func (d *fakeDocker) ExecInspect(ctx context.Context, execID string, options client.ExecInspectOptions) (client.ExecInspectResult, error) {
+	return client.ExecInspectResult{ID: execID}, nil
+}
+
+func (d *fakeDocker) check(nameOrID string) error {
+	if d.id == "" || (nameOrID != d.id && nameOrID != d.created.Name) {
+		return fmt.Errorf("container %q: %w", nameOrID, cerrdefs.ErrNotFound)
+	}
+	return nil
+}
+
+func TestBuildpacksRun(t *testing.T) {
+	dir := t.TempDir()
+	docker := newFakeDocker()
+	docker.stdout = "listening"
+	docker.stderr = "warning"
+	logs := newServerLogs(false)
+	logs.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	logs.setStep("startup")
+	b := &buildpacksFunctionServer{
+		logs:               logs,
+		containerRuntime:   dockerRuntime{},
+		docker:             docker,
+		name:               "conformance-test-func-run",
+		image:              "conformance-test-func-run",
+		temporaryImage:     true,
+		target:             "Func",
+		funcType:           "http",
+		envs:               []string{"A=B", ""},
+		functionOutputFile: "function_output.json",
+		stdoutFile:         filepath.Join(dir, "stdout.txt"),
+		stderrFile:         filepath.Join(dir, "stderr.txt"),
+	}
+
+	shutdown, err := b.run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got, want := docker.created.Name, b.name; got != want {
+		t.Errorf("container name = %q, want %q", got, want)
+	}
+	if got, want := docker.created.Config.Labels[runLabel], b.name; got != want {
+		t.Errorf("container label %s = %q, want %q", runLabel, got, want)
+	}
+	if got, want := strings.Join(docker.created.Config.Env, " "), "FUNCTION_TARGET=Func FUNCTION_SIGNATURE_TYPE=http A=B"; got != want {
+		t.Errorf("container env = %q, want %q", got, want)
+	}
+	if got, want := docker.created.HostConfig.NetworkMode, container.NetworkMode("host"); got != want {
+		t.Errorf("container network mode = %q, want %q", got, want)
+	}
+
+	docker.files["/workspace/function_output.json"] = []byte(`{"res": "PASS"}`)
+	output, err := b.OutputFile()
+	if err != nil {
+		t.Fatalf("OutputFile: %v", err)
+	}
+	if got, want := string(output), `{"res": "PASS"}`; got != want {
+		t.Errorf("OutputFile() = %q, want %q", got, want)
+	}
+	if err := b.ClearOutputFile(); err != nil {
+		t.Fatalf("ClearOutputFile: %v", err)
+	}
+	if _, err := b.OutputFile(); err == nil {
+		t.Errorf("OutputFile() after ClearOutputFile() got nil error, want error")
+	}
+
+	shutdown()
+	if got, want := docker.removed, []string{"id-" + b.name}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("removed containers = %v, want %v", got, want)
+	}
+	if got, want := docker.removedImages, []string{b.image}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("removed images = %v, want %v", got, want)
+	}
+	for file, want := range map[string]string{
+		b.stdoutFile: "2026-01-02T03:04:05.000Z [startup] listening\n",
+		b.stderrFile: "2026-01-02T03:04:05.000Z [startup] warning\n",
+	} {
+		got, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("reading logs: %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("logs in %s = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestBuildpacksRunEmulated(t *testing.T) {
+	dir := t.TempDir()
+	docker := newFakeDocker()
+	docker.images["conformance-test-func-run"] = true
+	b := &buildpacksFunctionServer{
+		containerRuntime:   dockerRuntime{},
+		docker:             docker,
+		name:               "conformance-test-func-run",
+		image:              "conformance-test-func-run",
+		profile:            &runtimeProfile{name: "gen2", memoryMB: 256, cpus: 1},
+		functionOutputFile: "function_output.json",
+		stdoutFile:         filepath.Join(dir, "stdout.txt"),
+		stderrFile:         filepath.Join(dir, "stderr.txt"),
+	}
+
+	shutdown, err := b.run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got, want := docker.created.Config.User, nobody; got != want {
+		t.Errorf("container user = %q, want %q", got, want)
+	}
+	binds := docker.created.HostConfig.Binds
+	if len(binds) != 1 || !strings.HasPrefix(binds[0], b.hostOutputFile+":") {
+		t.Fatalf("container binds = %v, want output file %s", binds, b.hostOutputFile)
+	}
+
+	if _, err := b.OutputFile(); err == nil {
+		t.Errorf("OutputFile() before output got nil error, want error")
+	}
+	if err := ioutil.WriteFile(b.hostOutputFile, []byte(`{"res": "PASS"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := b.OutputFile(); err != nil || string(output) != `{"res": "PASS"}` {
+		t.Errorf("OutputFile() = %q, %v, want %q", output, err, `{"res": "PASS"}`)
+	}
+	if err := b.ClearOutputFile(); err != nil {
+		t.Fatalf("ClearOutputFile: %v", err)
+	}
+	if _, err := b.OutputFile(); err == nil {
+		t.Errorf("OutputFile() after ClearOutputFile() got nil error, want error")
+	}
+
+	shutdown()
+	if _, err := os.Stat(b.hostOutputFile); !os.IsNotExist(err) {
+		t.Errorf("output file %s was not removed on shutdown", b.hostOutputFile)
+	}
+}
+
+func TestBuildpacksRunExited(t *testing.T) {
+	testCases := []struct {
+		name      string
+		exitCode  int
+		oomKilled bool
+		want      []string
+	}{
+		{
+			name:     "exited",
+			exitCode: 1,
+			want:     []string{"exited during startup: exit code 1", "[stderr] boom"},
+		},
+		{
+			name:      "out of memory",
+			exitCode:  137,
+			oomKilled: true,
+			want:      []string{"exit code 137, killed because it ran out of memory"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			docker := newFakeDocker()
+			docker.exitCode = tc.exitCode
+			docker.oomKilled = tc.oomKilled
+			docker.stderr = "boom\n"
+			b := &buildpacksFunctionServer{
+				containerRuntime: dockerRuntime{},
+				docker:           docker,
+				temporaryImage:   true,
+				logs:             newServerLogs(false),
+				stdoutFile:       filepath.Join(dir, "stdout.txt"),
+				stderrFile:       filepath.Join(dir, "stderr.txt"),
+			}
+
+			_, err := b.run(context.Background())
+			if err == nil {
+				t.Fatalf("run() got nil error for an exited container, want error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("run() got error %q, want it to contain %q", err, want)
+				}
+			}
+			if len(docker.removed) != 1 {
+				t.Errorf("exited container was not removed, removed containers = %v", docker.removed)
+			}
+			if len(docker.removedImages) != 1 {
+				t.Errorf("image of exited container was not removed, removed images = %v", docker.removedImages)
+			}
+		})
+	}
+}
+
+func TestBuildpacksRunLogFileError(t *testing.T) {
+	dir := t.TempDir()
+	docker := newFakeDocker()
+	b := &buildpacksFunctionServer{
+		containerRuntime: dockerRuntime{},
+		docker:           docker,
+		name:             "conformance-test-func-run",
+		image:            "conformance-test-func-run",
+		temporaryImage:   true,
+		stdoutFile:       filepath.Join(dir, "stdout.txt"),
+		stderrFile:       filepath.Join(dir, "missing", "stderr.txt"),
+	}
+
+	if _, err := b.run(context.Background()); err == nil {
+		t.Fatalf("run() got nil error for a log file that cannot be created, want error")
+	}
+	if len(docker.removedImages) != 1 {
+		t.Errorf("image was not removed, removed images = %v", docker.removedImages)
+	}
+	if err := b.logStdout.Close(); err == nil {
+		t.Errorf("stdout log file was not closed")
+	}
+}
+
+func TestBuildpacksInterrupted(t *testing.T) {
+	docker := newFakeDocker()
+	b := &buildpacksFunctionServer{
+		docker:         docker,
+		name:           "conformance-test-func-run",
+		image:          "conformance-test-func-run",
+		temporaryImage: true,
+		interrupt:      newExitWatcher(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b.interrupted(os.Interrupt, cancel)
+
+	if ctx.Err() == nil {
+		t.Errorf("interrupted() did not cancel the context")
+	}
+	if len(docker.removedImages) != 1 {
+		t.Errorf("image was not removed, removed images = %v", docker.removedImages)
+	}
+	if err := b.exitError(0); err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Errorf("exitError() = %v, want an interrupt error", err)
+	}
+	if err := b.interruptedOr(fmt.Errorf("building failed")); err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Errorf("interruptedOr() = %v, want an interrupt error", err)
+	}
+}
+
+func TestBuildpacksInterruptedPreparingImage(t *testing.T) {
+	docker := newFakeDocker()
+	docker.pulling = make(chan struct{})
+	b := &buildpacksFunctionServer{
+		docker:        docker,
+		name:          "conformance-test-func-run",
+		prebuiltImage: "example.com/function:v1",
+		interrupt:     newExitWatcher(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	// Canceling the pull as when interrupted makes the build fail and clean up before the
+	// interrupt handler does.
+	go func() {
+		<-docker.pulling
+		cancel()
+	}()
+
+	if _, err := b.launch(ctx, nil); err == nil {
+		t.Fatalf("launch() got nil error for a canceled pull, want error")
+	}
+	if err := b.cleanup(); err != nil {
+		t.Errorf("cleanup after canceling got error: %v", err)
+	}
+}
+
+func TestBuildpacksCleanup(t *testing.T) {
+	testCases := []struct {
+		name           string
+		temporaryImage bool
+		keepImage      bool
+		wantImages     int
+	}{
+		{
+			name:           "removes image",
+			temporaryImage: true,
+			wantImages:     1,
+		},
+		{
+			name:           "keeps image",
+			temporaryImage: true,
+			keepImage:      true,
+		},
+		{
+			name: "keeps pre-built or cached image",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			docker := newFakeDocker()
+			b := &buildpacksFunctionServer{
+				docker:         docker,
+				name:           "conformance-test-func-run",
+				image:          "conformance-test-func-run",
+				temporaryImage: tc.temporaryImage,
+				keepImage:      tc.keepImage,
+			}
+			// Cleaning up again, e.g. when interrupted during shutdown, does nothing.
+			for i := 0; i < 2; i++ {
+				if err := b.cleanup(); err != nil {
+					t.Fatalf("cleanup: %v", err)
+				}
+			}
+			if len(docker.removedImages) != tc.wantImages {
+				t.Errorf("removed images = %v, want %d", docker.removedImages, tc.wantImages)
+			}
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	a, err := uniqueName()
+	if err != nil {
+		t.Fatalf("uniqueName: %v", err)
+	}
+	b, err := uniqueName()
+	if err != nil {
+		t.Fatalf("uniqueName: %v", err)
+	}
+	if a == b || !strings.HasPrefix(a, namePrefix+"-") {
+		t.Errorf("uniqueName() = %q and %q, want distinct names starting with %q", a, b, namePrefix)
+	}
+}
+
+func TestBuildpacksPrepareImage(t *testing.T) {
+	source := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(source, "main.go"), []byte("package function"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cached := &buildpacksFunctionServer{builderURL: "builder", source: source, target: "Func"}
+	key, err := buildCacheKey(cached.buildOptions("builder"), "sha256:builder", "")
+	if err != nil {
+		t.Fatalf("buildCacheKey: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		b    *buildpacksFunctionServer
+		// images are the images available locally.
+		images    []string
+		wantImage string
+		wantPull  bool
+	}{
+		{
+			name:      "pre-built image",
+			b:         &buildpacksFunctionServer{prebuiltImage: "example.com/function:v1"},
+			wantImage: "example.com/function:v1",
+			wantPull:  true,
+		},
+		{
+			name:      "locally built pre-built image",
+			b:         &buildpacksFunctionServer{prebuiltImage: "function:local", pullPolicy: image.PullAlways},
+			images:    []string{"function:local"},
+			wantImage: "function:local",
+		},
+		{
+			name:      "cached image",
+			b:         &buildpacksFunctionServer{builderURL: "builder", source: source, target: "Func", cacheBuilds: true, pullPolicy: image.PullIfNotPresent},
+			images:    []string{"builder", cachedImage(key)},
+			wantImage: cachedImage(key),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			docker := newFakeDocker()
+			for _, ref := range tc.images {
+				docker.images[ref] = true
+			}
+			tc.b.docker = docker
+			// Building is not faked, so the cases must not build.
+			if err := tc.b.prepareImage(context.Background(), nil); err != nil {
+				t.Fatalf("prepareImage: %v", err)
+			}
+			if tc.b.image != tc.wantImage || tc.b.temporaryImage {
+				t.Errorf("prepareImage() image = %q (temporary %v), want %q", tc.b.image, tc.b.temporaryImage, tc.wantImage)
+			}
+			if gotPull := len(docker.pulled) > 0; gotPull != tc.wantPull {
+				t.Errorf("prepareImage() pulled %v, want pull %v", docker.pulled, tc.wantPull)
+			}
+		})
+	}
+}
+
+func TestEnsureImage(t *testing.T) {
+	testCases := []struct {
+		policy   image.PullPolicy
+		present  bool
+		wantPull bool
+		wantErr  bool
+	}{
+		{policy: image.PullAlways, present: true, wantPull: true},
+		{policy: image.PullAlways, wantPull: true},
+		{policy: image.PullIfNotPresent, present: true},
+		{policy: image.PullIfNotPresent, wantPull: true},
+		{policy: image.PullNever, present: true},
+		{policy: image.PullNever, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v present %v", tc.policy, tc.present), func(t *testing.T) {
+			docker := newFakeDocker()
+			docker.images["builder"] = tc.present
+			err := ensureImage(context.Background(), docker, "builder", tc.policy)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ensureImage() got error %v, want error %v", err, tc.wantErr)
+			}
+			if gotPull := len(docker.pulled) > 0; gotPull != tc.wantPull {
+				t.Errorf("ensureImage() pulled %v, want pull %v", docker.pulled, tc.wantPull)
+			}
+		})
+	}
+}
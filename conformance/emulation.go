@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+const (
+	// emulatedService is the name of the function, and of its Cloud Run service, in the emulated
+	// environment.
+	emulatedService = "conformance-test-func"
+	// nobody is the user a function runs as if its image runs as root.
+	nobody = "65534:65534"
+	mib    = 1 << 20
+)
+
+// runtimeProfile emulates the runtime environment of a generation of Cloud Functions in the
+// function container: the environment variables set by the platform, the memory and CPU limits,
+// a read-only file system except for /tmp and a non-root user.
+type runtimeProfile struct {
+	name string
+	// env are the environment variables set by the platform in addition to FUNCTION_TARGET and
+	// FUNCTION_SIGNATURE_TYPE.
+	env      []string
+	memoryMB int64
+	// cpus is the number of CPUs, which may be fractional.
+	cpus float64
+}
+
+// runtimeProfiles are the supported profiles, with the default limits of each generation.
+var runtimeProfiles = map[string]runtimeProfile{
+	"gen1": {
+		name: "gen1",
+		env: []string{
+			"FUNCTION_NAME=" + emulatedService,
+			"FUNCTION_MEMORY_MB=256",
+			"FUNCTION_TIMEOUT_SEC=60",
+			"FUNCTION_REGION=us-central1",
+			"GCP_PROJECT=conformance-test",
+			"K_SERVICE=" + emulatedService,
+			"K_REVISION=1",
+			"PORT=" + functionPort,
+		},
+		memoryMB: 256,
+		cpus:     0.167,
+	},
+	"gen2": {
+		name: "gen2",
+		env: []string{
+			"K_SERVICE=" + emulatedService,
+			"K_REVISION=" + emulatedService + "-00001-abc",
+			"K_CONFIGURATION=" + emulatedService,
+			"PORT=" + functionPort,
+			"LOG_EXECUTION_ID=true",
+		},
+		// Cloud Run requires at least 1 CPU for concurrent requests, which are validated.
+		memoryMB: 256,
+		cpus:     1,
+	},
+}
+
+// lookupRuntimeProfile returns the runtime profile with the given name, or nil if the name is
+// empty.
+func lookupRuntimeProfile(name string) (*runtimeProfile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	p, ok := runtimeProfiles[name]
+	if !ok {
+		var names []string
+		for n := range runtimeProfiles {
+			names = append(names, strconv.Quote(n))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unsupported runtime profile %q, want one of %s", name, strings.Join(names, ", "))
+	}
+	return &p, nil
+}
+
+// apply applies the profile to a container whose image runs as imageUser. The output file of the
+// function at outputPath is bind mounted from hostOutputFile, since the file system is read-only.
+// Environment variables of the container take precedence over those of the profile.
+func (p *runtimeProfile) apply(config *container.Config, hostConfig *container.HostConfig, imageUser, hostOutputFile, outputPath string) {
+	config.Env = append(append([]string{}, p.env...), config.Env...)
+	if isRoot(imageUser) {
+		config.User = nobody
+	}
+	hostConfig.Memory = p.memoryMB * mib
+	hostConfig.NanoCPUs = int64(p.cpus * 1e9)
+	hostConfig.ReadonlyRootfs = true
+	hostConfig.Tmpfs = map[string]string{"/tmp": ""}
+	hostConfig.Binds = append(hostConfig.Binds, hostOutputFile+":"+outputPath)
+}
+
+// isRoot returns whether a user of an image, as name or UID with an optional group, is root.
+func isRoot(user string) bool {
+	user, _, _ = strings.Cut(user, ":")
+	return user == "" || user == "root" || user == "0"
+}
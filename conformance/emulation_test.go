@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestRuntimeProfileApply(t *testing.T) {
+	testCases := []struct {
+		name      string
+		profile   string
+		imageUser string
+		wantEnv   []string
+		wantUser  string
+		wantCPUs  int64
+	}{
+		{
+			name:      "gen1 as root",
+			profile:   "gen1",
+			imageUser: "root",
+			wantEnv:   []string{"FUNCTION_NAME=conformance-test-func", "FUNCTION_MEMORY_MB=256", "PORT=8080"},
+			wantUser:  nobody,
+			wantCPUs:  167000000,
+		},
+		{
+			name:      "gen2 as image user",
+			profile:   "gen2",
+			imageUser: "cnb",
+			wantEnv:   []string{"K_SERVICE=conformance-test-func", "K_CONFIGURATION=conformance-test-func", "PORT=8080"},
+			wantCPUs:  1000000000,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := lookupRuntimeProfile(tc.profile)
+			if err != nil {
+				t.Fatalf("lookupRuntimeProfile(%q): %v", tc.profile, err)
+			}
+			config := &container.Config{Env: []string{"FUNCTION_TARGET=Func", "PORT=9090"}}
+			hostConfig := &container.HostConfig{}
+
+			p.apply(config, hostConfig, tc.imageUser, "/tmp/output", "/workspace/function_output.json")
+
+			env := strings.Join(config.Env, " ")
+			for _, want := range tc.wantEnv {
+				if !strings.Contains(env, want) {
+					t.Errorf("env = %q, want %q", env, want)
+				}
+			}
+			// Variables set for the container override those of the platform.
+			if !strings.HasSuffix(env, "FUNCTION_TARGET=Func PORT=9090") {
+				t.Errorf("env = %q, want it to end with the container variables", env)
+			}
+			if config.User != tc.wantUser {
+				t.Errorf("user = %q, want %q", config.User, tc.wantUser)
+			}
+			if hostConfig.Memory != 256*mib || hostConfig.NanoCPUs != tc.wantCPUs {
+				t.Errorf("memory and CPUs = %d and %d, want %d and %d", hostConfig.Memory, hostConfig.NanoCPUs, 256*mib, tc.wantCPUs)
+			}
+			if !hostConfig.ReadonlyRootfs {
+				t.Errorf("root file system is writable, want read-only")
+			}
+			if _, ok := hostConfig.Tmpfs["/tmp"]; !ok {
+				t.Errorf("tmpfs = %v, want /tmp", hostConfig.Tmpfs)
+			}
+			if got, want := strings.Join(hostConfig.Binds, " "), "/tmp/output:/workspace/function_output.json"; got != want {
+				t.Errorf("binds = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLookupRuntimeProfile(t *testing.T) {
+	if p, err := lookupRuntimeProfile(""); p != nil || err != nil {
+		t.Errorf("lookupRuntimeProfile(\"\") = %v, %v, want nil, nil", p, err)
+	}
+	if _, err := lookupRuntimeProfile("gen3"); err == nil {
+		t.Errorf("lookupRuntimeProfile(%q) got nil error, want error", "gen3")
+	}
+}
+
+func TestIsRoot(t *testing.T) {
+	for user, want := range map[string]bool{"": true, "root": true, "0:0": true, "cnb": false, "1000:1000": false} {
+		if got := isRoot(user); got != want {
+			t.Errorf("isRoot(%q) = %v, want %v", user, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// exitWait is how long to wait for a server that failed a request to be reported as exited.
+const exitWait = time.Second
+
+// serverExitError describes how a function server exited while it was being validated.
+type serverExitError struct {
+	// status is how the server exited, e.g. "exit status 1".
+	status string
+	// code is the exit code, or -1 if the server was killed by a signal.
+	code      int
+	oomKilled bool
+	// step is the validation step that was running, e.g. "startup".
+	step     string
+	lastLogs []string
+}
+
+func (e *serverExitError) Error() string {
+	var b strings.Builder
+	b.WriteString("function server exited ")
+	if e.step == "" || e.step == "startup" {
+		b.WriteString("during startup")
+	} else {
+		fmt.Fprintf(&b, "during %s", e.step)
+	}
+	fmt.Fprintf(&b, ": %s", e.status)
+	if e.oomKilled {
+		b.WriteString(", killed because it ran out of memory")
+	}
+	if len(e.lastLogs) > 0 {
+		b.WriteString("\nLast server logs:")
+		for _, line := range e.lastLogs {
+			fmt.Fprintf(&b, "\n\t%s", line)
+		}
+	}
+	return b.String()
+}
+
+// exitWatcher records that a function server exited while it was expected to run. A nil
+// *exitWatcher never reports an exit.
+type exitWatcher struct {
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+func newExitWatcher() *exitWatcher {
+	return &exitWatcher{done: make(chan struct{})}
+}
+
+// exited records that the server exited. Only the first exit is recorded.
+func (w *exitWatcher) exited(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.done)
+	})
+}
+
+// wait waits up to d for the server to exit, and returns the error describing the exit, or nil if
+// it is still running.
+func (w *exitWatcher) wait(d time.Duration) error {
+	if w == nil {
+		return nil
+	}
+	// An exit that was already recorded is returned even if d is 0.
+	select {
+	case <-w.done:
+		return w.err
+	default:
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-w.done:
+		return w.err
+	case <-t.C:
+		return nil
+	}
+}
+
+// exitReporter is implemented by function servers that report when they exit unexpectedly.
+type exitReporter interface {
+	// exitError waits up to d for the server to exit, and returns the error describing the exit,
+	// or nil if it is still running.
+	exitError(d time.Duration) error
+}
+
+// serverExit returns the error describing how the function server exited if it exited, waiting
+// briefly for the exit to be reported. It is called after the server failed a request.
+func (v *Validator) serverExit() error {
+	if r, ok := v.funcServer.(exitReporter); ok {
+		return r.exitError(exitWait)
+	}
+	return nil
+}
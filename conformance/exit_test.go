@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServerExitError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  *serverExitError
+		want string
+	}{
+		{
+			name: "during startup",
+			err:  &serverExitError{status: "exit status 1", step: "startup"},
+			want: "function server exited during startup: exit status 1",
+		},
+		{
+			name: "during request",
+			err: &serverExitError{
+				status:    "exit code 137",
+				oomKilled: true,
+				step:      `event "firebase-auth" (none, structured)`,
+				lastLogs:  []string{"[stdout] received event", "[stderr] out of memory"},
+			},
+			want: "function server exited during event \"firebase-auth\" (none, structured): exit code 137, killed because it ran out of memory\n" +
+				"Last server logs:\n\t[stdout] received event\n\t[stderr] out of memory",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.Error(); got != tc.want {
+				t.Errorf("Error() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExitWatcher(t *testing.T) {
+	var nilWatcher *exitWatcher
+	if err := nilWatcher.wait(0); err != nil {
+		t.Errorf("wait() on nil watcher = %v, want nil", err)
+	}
+
+	w := newExitWatcher()
+	if err := w.wait(0); err != nil {
+		t.Errorf("wait() before exit = %v, want nil", err)
+	}
+	first, second := errors.New("first"), errors.New("second")
+	w.exited(first)
+	w.exited(second)
+	for _, d := range []time.Duration{0, exitWait} {
+		if err := w.wait(d); err != first {
+			t.Errorf("wait(%v) after exit = %v, want %v", d, err, first)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+// maxShrinkAttempts bounds the number of requests sent to minimize a single failing input.
+const maxShrinkAttempts = 100
+
+// ValidateFuzz sends randomly generated events of every supported service and checks that they are
+// received as expected. Failing inputs are minimized and, if requested, exported as test data. Only
+// the results of failing inputs are returned.
+func (v *Validator) ValidateFuzz(url string, conversion EventConversion) ([]*events.ValidationInfo, error) {
+	inputType, outputType := conversion.Input, conversion.Output
+	log.Printf("Fuzzing with %d generated events, reproduce with -fuzz-seed=%d", v.fuzzCount, v.fuzzSeed)
+	r := rand.New(rand.NewSource(v.fuzzSeed))
+	services := events.FuzzServices()
+
+	vis := []*events.ValidationInfo{}
+	passed := 0
+	for i := 0; i < v.fuzzCount; i++ {
+		c, err := events.GenerateFuzzCase(r, services[i%len(services)])
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("fuzz_%s_%d_%d", c.Service, v.fuzzSeed, i)
+		vi, err := v.runFuzzCase(url, name, c, inputType, outputType)
+		if err != nil {
+			return nil, err
+		}
+		if vi.Errs == nil {
+			passed++
+			continue
+		}
+
+		c, vi, err = v.shrinkFuzzCase(url, name, c, vi, inputType, outputType)
+		if err != nil {
+			return nil, err
+		}
+		e := c.Event()
+		log.Printf("Minimized failing input %s:\n%s", name, e.InputData(inputType))
+		if v.fuzzExportDir != "" {
+			if err := events.WriteEventFiles(v.fuzzExportDir, name, e); err != nil {
+				return nil, fmt.Errorf("exporting %s: %v", name, err)
+			}
+		}
+		vis = append(vis, vi)
+	}
+	log.Printf("%d of %d generated events passed", passed, v.fuzzCount)
+	return vis, nil
+}
+
+// runFuzzCase sends a generated event and validates the function output. Errors sending the event
+// or reading the output are reported as validation errors, since the input may have caused them.
+func (v *Validator) runFuzzCase(url, name string, c events.FuzzCase, inputType, outputType events.EventType) (*events.ValidationInfo, error) {
+	if err := v.funcServer.ClearOutputFile(); err != nil {
+		return nil, fmt.Errorf("clearing output file before %q: %v", name, err)
+	}
+	v.logs.setStep("generated %s %q", inputType, name)
+	e := c.Event()
+	if err := send(url, inputType, e.InputData(inputType)); err != nil {
+		return &events.ValidationInfo{Name: name, Errs: []error{fmt.Errorf("failed to get response from function: %v", err)}}, nil
+	}
+	output, err := v.funcServer.OutputFile()
+	if err != nil {
+		return &events.ValidationInfo{Name: name, Errs: []error{fmt.Errorf("reading output file from function: %v", err)}}, nil
+	}
+	return events.ValidateEventData(name, e, inputType, outputType, output), nil
+}
+
+// shrinkFuzzCase greedily replaces a failing case by the first simpler variant that still fails,
+// until no variant fails or maxShrinkAttempts requests have been sent.
+func (v *Validator) shrinkFuzzCase(url, name string, c events.FuzzCase, vi *events.ValidationInfo, inputType, outputType events.EventType) (events.FuzzCase, *events.ValidationInfo, error) {
+	attempts := 0
+	for shrunk := true; shrunk && attempts < maxShrinkAttempts; {
+		shrunk = false
+		for _, candidate := range c.Shrink() {
+			if attempts >= maxShrinkAttempts {
+				break
+			}
+			attempts++
+			cvi, err := v.runFuzzCase(url, name, candidate, inputType, outputType)
+			if err != nil {
+				return c, vi, err
+			}
+			if cvi.Errs != nil {
+				c, vi, shrunk = candidate, cvi, true
+				break
+			}
+		}
+	}
+	return c, vi, nil
+}
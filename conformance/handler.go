@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+)
+
+// HandlerServer is a FunctionServer that serves an http.Handler in process with an httptest.Server,
+// so that Go Functions Frameworks can be validated without building and running a separate
+// process. Instead of writing what it receives to the function output file, the function passes
+// it to a callback.
+type HandlerServer struct {
+	newHandler func(output func([]byte)) http.Handler
+
+	server *httptest.Server
+
+	mu     sync.Mutex
+	output []byte
+}
+
+// NewHandlerServer returns a HandlerServer for the handler returned by newHandler. The function
+// served by the handler must call output with what it receives, in the format it would otherwise
+// write to the function output file.
+func NewHandlerServer(newHandler func(output func([]byte)) http.Handler) *HandlerServer {
+	return &HandlerServer{newHandler: newHandler}
+}
+
+// Start starts the server. Errors logged by the HTTP server, such as handler panics, are written
+// to stderrFile; nothing is written to stdoutFile. The function output file is not used.
+func (s *HandlerServer) Start(stdoutFile, stderrFile, functionOutputFile string) (func(), error) {
+	stdout, err := os.Create(stdoutFile)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := os.Create(stderrFile)
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+
+	s.server = httptest.NewUnstartedServer(s.newHandler(s.setOutput))
+	s.server.Config.ErrorLog = log.New(stderr, "", log.LstdFlags)
+	s.server.Start()
+	log.Printf("Framework server started at %s.", s.server.URL)
+
+	shutdown := func() {
+		s.server.Close()
+		stdout.Close()
+		stderr.Close()
+		log.Printf("Framework server shut down.")
+	}
+	return shutdown, nil
+}
+
+// URL returns the URL of the started server.
+func (s *HandlerServer) URL() string {
+	return s.server.URL
+}
+
+func (s *HandlerServer) setOutput(output []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = append([]byte{}, output...)
+}
+
+// OutputFile returns the output the function last passed to its callback.
+func (s *HandlerServer) OutputFile() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.output == nil {
+		return nil, errors.New("function did not report any output")
+	}
+	return s.output, nil
+}
+
+// ClearOutputFile discards the output the function last passed to its callback.
+func (s *HandlerServer) ClearOutputFile() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = nil
+	return nil
+}
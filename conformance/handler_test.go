@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandlerServer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler func(output func([]byte)) http.Handler
+		wantErr bool
+	}{
+		{
+			name: "echo",
+			handler: func(output func([]byte)) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, err := ioutil.ReadAll(r.Body)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					output(body)
+				})
+			},
+		},
+		{
+			name: "no output",
+			handler: func(output func([]byte)) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong output",
+			handler: func(output func([]byte)) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					output([]byte(`{"res": "FAIL"}`))
+				})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			v := NewValidator(ValidatorParams{
+				FunctionServer:    NewHandlerServer(tc.handler),
+				FunctionSignature: "http",
+			})
+			v.stdoutFile = filepath.Join(dir, "stdout.txt")
+			v.stderrFile = filepath.Join(dir, "stderr.txt")
+
+			err := v.Run()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Run() got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandlerServerClearOutputFile(t *testing.T) {
+	s := NewHandlerServer(nil)
+	s.setOutput([]byte{})
+	if _, err := s.OutputFile(); err != nil {
+		t.Errorf("OutputFile() after empty output got error %v, want nil", err)
+	}
+	if err := s.ClearOutputFile(); err != nil {
+		t.Fatalf("ClearOutputFile: %v", err)
+	}
+	if _, err := s.OutputFile(); err == nil {
+		t.Errorf("OutputFile() after ClearOutputFile() got nil error, want error")
+	}
+}
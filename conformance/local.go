@@ -0,0 +1,153 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type localFunctionServer struct {
+	functionOutputFile string
+	cmd                string
+	stdoutFile         string
+	stderrFile         string
+	envs               []string
+	// unsetEnvs are removed from the environment of the server, including envs.
+	unsetEnvs []string
+	// args are appended to the arguments of cmd.
+	args       []string
+	startDelay time.Duration
+	logs       *serverLogs
+	exit       *exitWatcher
+	stopping   atomic.Bool
+}
+
+func (l *localFunctionServer) Start(stdoutFile, stderrFile, functionOutputFile string) (func(), error) {
+	l.stdoutFile = stdoutFile
+	l.stderrFile = stderrFile
+	l.functionOutputFile = functionOutputFile
+	args := append(strings.Fields(l.cmd), l.args...)
+	cmd := newCmd(args)
+
+	stdout, err := l.logs.create(l.stdoutFile, "stdout")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = stdout
+
+	stderr, err := l.logs.create(l.stderrFile, "stderr")
+	if err != nil {
+		stdout.Close()
+		os.Remove(l.stdoutFile)
+		return nil, err
+	}
+	cmd.Stderr = stderr
+	// Stop copying the logs soon after the server exits, even if a process it started still has
+	// them open.
+	cmd.WaitDelay = time.Second
+	cmd.Env = os.Environ()
+	for _, s := range l.envs {
+		if s != "" {
+			cmd.Env = append(cmd.Env, s)
+		}
+	}
+	cmd.Env = removeEnvs(cmd.Env, l.unsetEnvs)
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Framework server started.")
+
+	l.exit = newExitWatcher()
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		// Waiting also copies the rest of the logs.
+		cmd.Wait()
+		if !l.stopping.Load() {
+			l.exit.exited(&serverExitError{
+				status:   cmd.ProcessState.String(),
+				code:     cmd.ProcessState.ExitCode(),
+				step:     l.logs.currentStep(),
+				lastLogs: l.logs.lastLines(),
+			})
+		}
+	}()
+
+	// Give it some time to do its setup, unless it exits.
+	if err := l.exit.wait(l.startDelay); err != nil {
+		stdout.Close()
+		stderr.Close()
+		return nil, err
+	}
+
+	shutdown := func() {
+		l.stopping.Store(true)
+		// A server that exited has no process to stop.
+		if l.exit.wait(0) == nil {
+			if err := stopCmd(cmd); err != nil {
+				log.Printf("Failed to shut down framework server: %v", err)
+			}
+		}
+		<-waited
+		stdout.Close()
+		stderr.Close()
+
+		log.Printf("Framework server shut down. Wrote logs to %v and %v.", l.stdoutFile, l.stderrFile)
+	}
+	return shutdown, nil
+}
+
+// removeEnvs returns the environment variables of env, as KEY=VALUE, except those of keys.
+func removeEnvs(env, keys []string) []string {
+	if len(keys) == 0 {
+		return env
+	}
+	var kept []string
+	for _, s := range env {
+		key, _, _ := strings.Cut(s, "=")
+		removed := false
+		for _, k := range keys {
+			if key == k {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+func (l *localFunctionServer) exitError(d time.Duration) error {
+	return l.exit.wait(d)
+}
+
+func (l *localFunctionServer) OutputFile() ([]byte, error) {
+	return ioutil.ReadFile(l.functionOutputFile)
+}
+
+func (l *localFunctionServer) ClearOutputFile() error {
+	if err := os.Remove(l.functionOutputFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -14,7 +14,7 @@
 
 // +build !windows
 
-package main
+package conformance
 
 import (
 	"fmt"
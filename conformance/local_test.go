@@ -0,0 +1,127 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testProgram = `package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	sleepDuration := time.Second * 90
+	fmt.Printf("Hello from test program. Sleeping for %v.\n", sleepDuration)
+	time.Sleep(sleepDuration)
+}
+`
+
+const exitingProgram = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "boom")
+	os.Exit(3)
+}
+`
+
+func TestStartAndShutdown(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(f, []byte(testProgram), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	outputFile := filepath.Join(dir, "function_output.json")
+
+	server := localFunctionServer{
+		// `go run` compiles the program and then execs it, which allows us to test that
+		// the whole process group is killed. It's done this way instead of something
+		// simpler like "/bin/sh -c 'exec sleep 90'" so that it's cross-platform compatible.
+		cmd: fmt.Sprintf("go run %s", f),
+	}
+
+	shutdown, err := server.Start(defaultStdoutFile, defaultStderrFile, outputFile)
+	if shutdown != nil {
+		defer shutdown()
+	}
+
+	if err != nil {
+		t.Errorf("unable to start localFunctionServer: %v", err)
+	}
+}
+
+func TestStartExited(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(f, []byte(exitingProgram), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	server := localFunctionServer{
+		cmd:        fmt.Sprintf("go run %s", f),
+		startDelay: 5 * time.Minute,
+		logs:       newServerLogs(false),
+	}
+
+	start := time.Now()
+	shutdown, err := server.Start(filepath.Join(dir, "stdout.txt"), filepath.Join(dir, "stderr.txt"), filepath.Join(dir, "function_output.json"))
+	if shutdown != nil {
+		defer shutdown()
+		t.Fatalf("localFunctionServer started a server that exited")
+	}
+	if time.Since(start) >= server.startDelay {
+		t.Errorf("localFunctionServer waited for the start delay after the server exited")
+	}
+	// `go run` exits with status 1 and logs the status of the program.
+	for _, want := range []string{"exited during startup: exit status 1", "[stderr] boom"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("localFunctionServer.Start() got error %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestStartLogFileError(t *testing.T) {
+	dir := t.TempDir()
+	server := localFunctionServer{
+		cmd:  "go version",
+		logs: newServerLogs(false),
+	}
+	stdoutFile := filepath.Join(dir, "stdout.txt")
+
+	shutdown, err := server.Start(stdoutFile, filepath.Join(dir, "missing", "stderr.txt"), filepath.Join(dir, "function_output.json"))
+	if shutdown != nil {
+		defer shutdown()
+	}
+	if err == nil {
+		t.Fatalf("localFunctionServer.Start() got nil error for a log file that cannot be created, want error")
+	}
+	if _, err := os.Stat(stdoutFile); !os.IsNotExist(err) {
+		t.Errorf("stdout log file was not removed, stat got error %v", err)
+	}
+}
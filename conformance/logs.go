@@ -0,0 +1,156 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	logTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+	// recentLines is the number of lines kept to report when a server exits.
+	recentLines = 10
+)
+
+// serverLogs annotates each line logged by a function server with the time it was written and the
+// validation step that was running, so that what the server logged can be related to the request
+// that caused it. If stream is true, the lines are also logged to the console as they are written.
+// A nil *serverLogs writes lines without a step and does not stream them.
+type serverLogs struct {
+	stream bool
+
+	mu     sync.Mutex
+	step   string
+	now    func() time.Time
+	recent []string
+}
+
+func newServerLogs(stream bool) *serverLogs {
+	return &serverLogs{stream: stream, now: time.Now}
+}
+
+// setStep sets the validation step that is running.
+func (l *serverLogs) setStep(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.step = fmt.Sprintf(format, args...)
+}
+
+// currentStep returns the validation step that is running.
+func (l *serverLogs) currentStep() string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.step
+}
+
+// lastLines returns the last lines logged by the server, prefixed with their stream.
+func (l *serverLogs) lastLines() []string {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.recent...)
+}
+
+// record returns the time and the step to annotate a line with, and keeps the line as one of the
+// last lines.
+func (l *serverLogs) record(stream string, line []byte) (time.Time, string) {
+	if l == nil {
+		return time.Now(), ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.recent = append(l.recent, fmt.Sprintf("[%s] %s", stream, line))
+	if len(l.recent) > recentLines {
+		l.recent = l.recent[len(l.recent)-recentLines:]
+	}
+	return l.now(), l.step
+}
+
+// create creates a log file for the given stream of the server, "stdout" or "stderr".
+func (l *serverLogs) create(file, stream string) (io.WriteCloser, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	return &logWriter{logs: l, stream: stream, file: f}, nil
+}
+
+// logWriter writes the annotated lines of a server stream to a file.
+type logWriter struct {
+	logs   *serverLogs
+	stream string
+
+	mu   sync.Mutex
+	file *os.File
+	// partial is the last line written, until it ends.
+	partial []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.partial[:i]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+}
+
+func (w *logWriter) writeLine(line []byte) error {
+	t, step := w.logs.record(w.stream, line)
+	if step == "" {
+		step = "-"
+	}
+	if w.logs != nil && w.logs.stream {
+		log.Printf("[%s] [%s] %s", w.stream, step, line)
+	}
+	_, err := fmt.Fprintf(w.file, "%s [%s] %s\n", t.Format(logTimeFormat), step, line)
+	return err
+}
+
+// Close writes a last line that did not end and closes the file.
+func (w *logWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var err error
+	if len(w.partial) > 0 {
+		err = w.writeLine(w.partial)
+		w.partial = nil
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerLogs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		stream bool
+		// write writes to the log, setting steps in between.
+		write       func(l *serverLogs, w io.Writer)
+		wantFile    string
+		wantConsole []string
+	}{
+		{
+			name: "annotates lines with step",
+			write: func(l *serverLogs, w io.Writer) {
+				io.WriteString(w, "starting\n")
+				l.setStep("event %q", "storage")
+				io.WriteString(w, "received\nhandled\n")
+			},
+			wantFile: "2026-01-02T03:04:05.000Z [-] starting\n" +
+				"2026-01-02T03:04:05.000Z [event \"storage\"] received\n" +
+				"2026-01-02T03:04:05.000Z [event \"storage\"] handled\n",
+		},
+		{
+			name: "joins partial lines",
+			write: func(l *serverLogs, w io.Writer) {
+				io.WriteString(w, "hel")
+				io.WriteString(w, "lo\nwor")
+				io.WriteString(w, "ld")
+			},
+			wantFile: "2026-01-02T03:04:05.000Z [-] hello\n" +
+				"2026-01-02T03:04:05.000Z [-] world\n",
+		},
+		{
+			name:   "streams lines",
+			stream: true,
+			write: func(l *serverLogs, w io.Writer) {
+				l.setStep("http")
+				io.WriteString(w, "request\n")
+			},
+			wantFile:    "2026-01-02T03:04:05.000Z [http] request\n",
+			wantConsole: []string{"[stdout] [http] request"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var console bytes.Buffer
+			defer log.SetOutput(log.Writer())
+			log.SetOutput(&console)
+
+			l := newServerLogs(tc.stream)
+			l.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+			file := filepath.Join(t.TempDir(), "stdout.txt")
+			w, err := l.create(file, "stdout")
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			tc.write(l, w)
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			got, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("reading logs: %v", err)
+			}
+			if string(got) != tc.wantFile {
+				t.Errorf("logs = %q, want %q", got, tc.wantFile)
+			}
+			for _, want := range tc.wantConsole {
+				if !strings.Contains(console.String(), want) {
+					t.Errorf("console = %q, want %q", console.String(), want)
+				}
+			}
+			if len(tc.wantConsole) == 0 && console.Len() > 0 {
+				t.Errorf("console = %q, want no output", console.String())
+			}
+		})
+	}
+}
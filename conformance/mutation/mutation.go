@@ -0,0 +1,390 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mutation tests the event validators by mutation: it runs the validators against the
+// reference server with mutations applied to what its functions receive, and reports which
+// mutations the validators fail to detect. A surviving mutation means that a validator accepts
+// wrong output.
+package mutation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance"
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance/reference"
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+// Mutation is a change to the event a function receives.
+type Mutation struct {
+	Name        string
+	Description string
+	// cloudEvent and legacyEvent mutate the JSON representation of a CloudEvent, or of a legacy
+	// event and its context. They return false if the mutation does not apply to the event.
+	cloudEvent  func(ce map[string]interface{}) bool
+	legacyEvent func(e map[string]interface{}) bool
+}
+
+// Mutations are the mutations applied to every event.
+var Mutations = []Mutation{
+	{
+		Name:        "drop-subject",
+		Description: "removes the CloudEvent subject",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return deleteKey(ce, "subject")
+		},
+	},
+	{
+		Name:        "swap-id",
+		Description: "replaces the event ID",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return mutateString(ce, "id", swapped)
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return mutateString(context(e), "eventId", swapped)
+		},
+	},
+	{
+		Name:        "drop-id",
+		Description: "removes the event ID",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return deleteKey(ce, "id")
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return deleteKey(context(e), "eventId")
+		},
+	},
+	{
+		Name:        "alter-time",
+		Description: "moves the event time one second later",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return mutateString(ce, "time", later)
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return mutateString(context(e), "timestamp", later)
+		},
+	},
+	{
+		Name:        "alter-type",
+		Description: "changes the event type",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return mutateString(ce, "type", suffixed)
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return mutateString(context(e), "eventType", suffixed)
+		},
+	},
+	{
+		Name:        "alter-resource",
+		Description: "changes the CloudEvent source or the legacy event resource name",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return mutateString(ce, "source", suffixed)
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			c := context(e)
+			if r, ok := c["resource"].(map[string]interface{}); ok {
+				return mutateString(r, "name", suffixed)
+			}
+			return mutateString(c, "resource", suffixed)
+		},
+	},
+	{
+		Name:        "rename-data-key",
+		Description: "renames the first property of the event data",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return renameFirstKey(ce["data"])
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return renameFirstKey(e["data"])
+		},
+	},
+	{
+		Name:        "drop-data-key",
+		Description: "removes the first property of the event data",
+		cloudEvent: func(ce map[string]interface{}) bool {
+			return deleteFirstKey(ce["data"])
+		},
+		legacyEvent: func(e map[string]interface{}) bool {
+			return deleteFirstKey(e["data"])
+		},
+	},
+}
+
+func context(e map[string]interface{}) map[string]interface{} {
+	c, _ := e["context"].(map[string]interface{})
+	return c
+}
+
+func deleteKey(m map[string]interface{}, key string) bool {
+	if _, ok := m[key]; !ok {
+		return false
+	}
+	delete(m, key)
+	return true
+}
+
+func mutateString(m map[string]interface{}, key string, mutate func(string) string) bool {
+	s, ok := m[key].(string)
+	if !ok {
+		return false
+	}
+	m[key] = mutate(s)
+	return true
+}
+
+func swapped(id string) string {
+	return "mutated-" + id
+}
+
+func later(timestamp string) string {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return timestamp + "x"
+	}
+	return t.Add(time.Second).Format(time.RFC3339Nano)
+}
+
+func suffixed(s string) string {
+	return s + "-mutated"
+}
+
+func firstKey(data interface{}) (map[string]interface{}, string, bool) {
+	m, ok := data.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil, "", false
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return m, keys[0], true
+}
+
+func renameFirstKey(data interface{}) bool {
+	m, key, ok := firstKey(data)
+	if !ok {
+		return false
+	}
+	m[key+"Mutated"] = m[key]
+	delete(m, key)
+	return true
+}
+
+func deleteFirstKey(data interface{}) bool {
+	m, key, ok := firstKey(data)
+	if !ok {
+		return false
+	}
+	delete(m, key)
+	return true
+}
+
+// apply returns the output with the mutation applied, or false if it does not apply.
+func (m Mutation) apply(t events.EventType, output []byte) ([]byte, bool) {
+	mutate := m.cloudEvent
+	if t == events.LegacyEvent {
+		mutate = m.legacyEvent
+	}
+	if mutate == nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(output))
+	dec.UseNumber()
+	var e map[string]interface{}
+	if err := dec.Decode(&e); err != nil || !mutate(e) {
+		return nil, false
+	}
+	mutated, err := json.Marshal(e)
+	if err != nil {
+		return nil, false
+	}
+	return mutated, true
+}
+
+// CaseResult is the result of mutating the event of one test case and conversion.
+type CaseResult struct {
+	Name       string
+	Conversion conformance.EventConversion
+	// Killed are the mutations the validators detected.
+	Killed []string
+	// Survived are the mutations the validators did not detect.
+	Survived []string
+}
+
+// Report is the result of mutation testing.
+type Report struct {
+	Cases []CaseResult
+}
+
+// KillRate returns the fraction of applicable mutations that were detected.
+func (r Report) KillRate() float64 {
+	var killed, total int
+	for _, c := range r.Cases {
+		killed += len(c.Killed)
+		total += len(c.Killed) + len(c.Survived)
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(killed) / float64(total)
+}
+
+// Survivors returns the number of mutations that were not detected.
+func (r Report) Survivors() int {
+	var n int
+	for _, c := range r.Cases {
+		n += len(c.Survived)
+	}
+	return n
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Mutation kill rate: %.1f%% (%d surviving mutations)\n", 100*r.KillRate(), r.Survivors())
+	for _, c := range r.Cases {
+		total := len(c.Killed) + len(c.Survived)
+		fmt.Fprintf(&b, "\t- %s (%s): %d/%d killed", c.Name, c.Conversion, len(c.Killed), total)
+		if len(c.Survived) > 0 {
+			fmt.Fprintf(&b, ", survived: %s", strings.Join(c.Survived, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Run runs the event validators for a declarative signature, "cloudevent" or "legacyevent",
+// against the reference server with each mutation applied to each test case. If validateMapping
+// is true, conversions from the other event type are mutated too. The returned error is set if
+// the reference server could not be validated, e.g. if it fails a test case without mutation.
+func Run(signature string, validateMapping bool) (Report, error) {
+	if signature != "cloudevent" && signature != "legacyevent" {
+		return Report{}, fmt.Errorf("unsupported signature %q, want 'cloudevent' or 'legacyevent'", signature)
+	}
+
+	var (
+		mu     sync.Mutex
+		mutate func([]byte) []byte
+	)
+	server := conformance.NewHandlerServer(func(output func([]byte)) http.Handler {
+		h, err := reference.NewHandler(reference.Options{Signature: signature}, func(b []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			if mutate != nil {
+				b = mutate(b)
+			}
+			output(b)
+		})
+		if err != nil {
+			// Unreachable, the signature is checked above.
+			panic(err)
+		}
+		return h
+	})
+
+	v := conformance.NewValidator(conformance.ValidatorParams{
+		FunctionServer:       server,
+		FunctionSignature:    signature,
+		DeclarativeSignature: signature,
+		ValidateMapping:      validateMapping,
+	})
+	shutdown, err := v.Start()
+	if err != nil {
+		return Report{}, err
+	}
+	defer shutdown()
+	url := v.URL()
+
+	setMutation := func(f func([]byte) []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		mutate = f
+	}
+	var report Report
+	for _, c := range v.EventConversions() {
+		names, err := events.EventNames(c.Input)
+		if err != nil {
+			return Report{}, err
+		}
+		for _, name := range names {
+			setMutation(nil)
+			vis, err := v.ValidateEvent(url, name, c)
+			if err != nil {
+				return Report{}, err
+			}
+			switch {
+			case failed(vis):
+				return Report{}, fmt.Errorf("reference server fails %q (%s) without mutation", name, c)
+			case skipped(vis):
+				// There is no expected output to compare mutations against.
+				continue
+			}
+
+			result := CaseResult{Name: name, Conversion: c}
+			for _, m := range Mutations {
+				m := m
+				applied := false
+				setMutation(func(b []byte) []byte {
+					mutated, ok := m.apply(c.Output, b)
+					if !ok {
+						return b
+					}
+					applied = true
+					return mutated
+				})
+				vis, err := v.ValidateEvent(url, name, c)
+				if err != nil {
+					return Report{}, err
+				}
+				mu.Lock()
+				wasApplied := applied
+				mu.Unlock()
+				switch {
+				case !wasApplied:
+				case failed(vis):
+					result.Killed = append(result.Killed, m.Name)
+				default:
+					result.Survived = append(result.Survived, m.Name)
+				}
+			}
+			report.Cases = append(report.Cases, result)
+		}
+	}
+	return report, nil
+}
+
+func failed(vis []*events.ValidationInfo) bool {
+	for _, vi := range vis {
+		if vi.Errs != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func skipped(vis []*events.ValidationInfo) bool {
+	for _, vi := range vis {
+		if vi.SkippedReason != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutation
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+// TestValidatorsKillMutations fails if a validator accepts a mutated event. If a new mutation or
+// test case makes it fail, the validators need to be fixed, not the test.
+func TestValidatorsKillMutations(t *testing.T) {
+	for _, signature := range []string{"cloudevent", "legacyevent"} {
+		t.Run(signature, func(t *testing.T) {
+			report, err := Run(signature, true)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if len(report.Cases) == 0 {
+				t.Fatalf("Run() returned no test cases")
+			}
+			for _, c := range report.Cases {
+				if len(c.Killed)+len(c.Survived) == 0 {
+					t.Errorf("no mutation applies to %q (%s)", c.Name, c.Conversion)
+				}
+			}
+			if report.Survivors() > 0 {
+				t.Errorf("validators did not detect mutations:\n%s", report)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mutation string
+		t        events.EventType
+		output   string
+		want     string
+		ok       bool
+	}{
+		{
+			name:     "cloud event",
+			mutation: "drop-subject",
+			t:        events.CloudEvent,
+			output:   `{"id": "1", "subject": "users/1"}`,
+			want:     `{"id":"1"}`,
+			ok:       true,
+		},
+		{
+			name:     "not applicable",
+			mutation: "drop-subject",
+			t:        events.CloudEvent,
+			output:   `{"id": "1"}`,
+		},
+		{
+			name:     "no legacy mutation",
+			mutation: "drop-subject",
+			t:        events.LegacyEvent,
+			output:   `{"context": {"eventId": "1"}}`,
+		},
+		{
+			name:     "legacy event",
+			mutation: "alter-time",
+			t:        events.LegacyEvent,
+			output:   `{"context": {"timestamp": "2020-09-29T11:32:00.123Z"}, "data": 1.50}`,
+			want:     `{"context":{"timestamp":"2020-09-29T11:32:01.123Z"},"data":1.50}`,
+			ok:       true,
+		},
+		{
+			name:     "legacy resource object",
+			mutation: "alter-resource",
+			t:        events.LegacyEvent,
+			output:   `{"context": {"resource": {"name": "projects/p"}}}`,
+			want:     `{"context":{"resource":{"name":"projects/p-mutated"}}}`,
+			ok:       true,
+		},
+		{
+			name:     "data key",
+			mutation: "rename-data-key",
+			t:        events.CloudEvent,
+			output:   `{"data": {"b": 2, "a": 1}}`,
+			want:     `{"data":{"aMutated":1,"b":2}}`,
+			ok:       true,
+		},
+	}
+
+	mutations := map[string]Mutation{}
+	for _, m := range Mutations {
+		mutations[m.Name] = m
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := mutations[tc.mutation].apply(tc.t, []byte(tc.output))
+			if ok != tc.ok {
+				t.Fatalf("apply() ok = %v, want %v", ok, tc.ok)
+			}
+			if string(got) != tc.want {
+				t.Errorf("apply() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
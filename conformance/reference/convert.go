@@ -0,0 +1,375 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// The tables below are copied from docs/mapping.md rather than shared with the events package, so
+// that the reference server is an independent implementation of the mapping, as a Functions
+// Framework would be.
+
+// cloudEventTypes maps legacy event types to CloudEvent types.
+var cloudEventTypes = map[string]string{
+	"google.pubsub.topic.publish":                              "google.cloud.pubsub.topic.v1.messagePublished",
+	"providers/cloud.pubsub/eventTypes/topic.publish":          "google.cloud.pubsub.topic.v1.messagePublished",
+	"google.storage.object.finalize":                           "google.cloud.storage.object.v1.finalized",
+	"google.storage.object.delete":                             "google.cloud.storage.object.v1.deleted",
+	"google.storage.object.archive":                            "google.cloud.storage.object.v1.archived",
+	"google.storage.object.metadataUpdate":                     "google.cloud.storage.object.v1.metadataUpdated",
+	"providers/cloud.firestore/eventTypes/document.write":      "google.cloud.firestore.document.v1.written",
+	"providers/cloud.firestore/eventTypes/document.create":     "google.cloud.firestore.document.v1.created",
+	"providers/cloud.firestore/eventTypes/document.update":     "google.cloud.firestore.document.v1.updated",
+	"providers/cloud.firestore/eventTypes/document.delete":     "google.cloud.firestore.document.v1.deleted",
+	"providers/firebase.auth/eventTypes/user.create":           "google.firebase.auth.user.v1.created",
+	"providers/firebase.auth/eventTypes/user.delete":           "google.firebase.auth.user.v1.deleted",
+	"providers/firebase.remoteConfig/remoteconfig.update":      "google.firebase.remoteconfig.remoteConfig.v1.updated",
+	"providers/google.firebase.analytics/eventTypes/event.log": "google.firebase.analytics.log.v1.written",
+	"providers/google.firebase.database/eventTypes/ref.create": "google.firebase.database.ref.v1.created",
+	"providers/google.firebase.database/eventTypes/ref.write":  "google.firebase.database.ref.v1.written",
+	"providers/google.firebase.database/eventTypes/ref.update": "google.firebase.database.ref.v1.updated",
+	"providers/google.firebase.database/eventTypes/ref.delete": "google.firebase.database.ref.v1.deleted",
+}
+
+// servicePrefixes maps legacy event type prefixes to the service of events without a
+// context/resource/service value.
+var servicePrefixes = map[string]string{
+	"providers/cloud.firestore/":           "firestore.googleapis.com",
+	"providers/google.firebase.analytics/": "firebaseanalytics.googleapis.com",
+	"providers/firebase.auth/":             "firebaseauth.googleapis.com",
+	"providers/google.firebase.database/":  "firebasedatabase.googleapis.com",
+	"providers/cloud.pubsub/":              "pubsub.googleapis.com",
+	"providers/cloud.storage/":             "storage.googleapis.com",
+}
+
+// legacyResourceTypes maps services to the type of legacy event resource objects.
+var legacyResourceTypes = map[string]string{
+	"pubsub.googleapis.com":  "type.googleapis.com/google.pubsub.v1.PubsubMessage",
+	"storage.googleapis.com": "storage#object",
+}
+
+const (
+	pubSubService    = "pubsub.googleapis.com"
+	storageService   = "storage.googleapis.com"
+	firestoreService = "firestore.googleapis.com"
+	databaseService  = "firebasedatabase.googleapis.com"
+	authService      = "firebaseauth.googleapis.com"
+	analyticsService = "firebaseanalytics.googleapis.com"
+)
+
+// authMetadataNames maps the names of Firebase Auth metadata timestamps in legacy events to their
+// names in CloudEvents.
+var authMetadataNames = map[string]string{
+	"createdAt":      "createTime",
+	"lastSignedInAt": "lastSignInTime",
+}
+
+// legacyEvent is an event in the GCF HTTP representation.
+type legacyEvent struct {
+	id        string
+	timestamp time.Time
+	eventType string
+	// resource is the resource as received, either a string or an object.
+	resource interface{}
+	data     interface{}
+	// domain is the top-level domain property of Firebase RTDB events.
+	domain interface{}
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// parseLegacyEvent parses an event in the GCF HTTP representation, whose context is either in the
+// context property or at the root.
+func parseLegacyEvent(body []byte) (*legacyEvent, error) {
+	var root map[string]interface{}
+	if err := decodeJSON(body, &root); err != nil {
+		return nil, fmt.Errorf("parsing legacy event: %v", err)
+	}
+	context, ok := root["context"].(map[string]interface{})
+	if !ok {
+		context = root
+	}
+
+	e := &legacyEvent{
+		resource: context["resource"],
+		data:     root["data"],
+		domain:   root["domain"],
+	}
+	e.id, _ = context["eventId"].(string)
+	if e.id == "" {
+		return nil, errors.New("legacy event has no eventId")
+	}
+	e.eventType, _ = context["eventType"].(string)
+	if e.eventType == "" {
+		return nil, errors.New("legacy event has no eventType")
+	}
+	timestamp, _ := context["timestamp"].(string)
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("parsing legacy event timestamp: %v", err)
+	}
+	e.timestamp = t
+	return e, nil
+}
+
+// output returns the function output for a legacy event: the event data and context.
+func (e *legacyEvent) output() map[string]interface{} {
+	return map[string]interface{}{
+		"data": e.data,
+		"context": map[string]interface{}{
+			"eventId":   e.id,
+			"timestamp": e.timestamp.Format(time.RFC3339Nano),
+			"eventType": e.eventType,
+			"resource":  e.resource,
+		},
+	}
+}
+
+// toCloudEvent converts a legacy event to a CloudEvent as described in docs/mapping.md.
+func (e *legacyEvent) toCloudEvent(faults faultSet) (*cloudevents.Event, error) {
+	ceType, ok := cloudEventTypes[e.eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown legacy event type %q", e.eventType)
+	}
+
+	var service, name string
+	switch r := e.resource.(type) {
+	case string:
+		name = r
+	case map[string]interface{}:
+		name, _ = r["name"].(string)
+		service, _ = r["service"].(string)
+	}
+	if name == "" {
+		return nil, errors.New("legacy event has no resource name")
+	}
+	if service == "" {
+		for prefix, s := range servicePrefixes {
+			if strings.HasPrefix(e.eventType, prefix) {
+				service = s
+			}
+		}
+	}
+	if service == "" {
+		return nil, fmt.Errorf("unable to determine the service of legacy event type %q", e.eventType)
+	}
+
+	source, subject, data := name, "", e.data
+	switch service {
+	case storageService:
+		if i := strings.Index(name, "/objects/"); i >= 0 {
+			source = name[:i]
+			subject = strings.SplitN(name[i+1:], "#", 2)[0]
+		}
+	case pubSubService:
+		message, ok := e.data.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("Pub/Sub event data is not an object")
+		}
+		message = copyMap(message)
+		if !faults.has(FaultPubSubMessage) {
+			message["messageId"] = e.id
+			message["publishTime"] = e.timestamp.Format(time.RFC3339Nano)
+		}
+		data = map[string]interface{}{"message": message}
+	case firestoreService:
+		if i := strings.Index(name, "/documents/"); i >= 0 {
+			source, subject = name[:i], name[i+1:]
+		}
+	case databaseService:
+		location := "us-central1"
+		if !faults.has(FaultRTDBLocation) {
+			domain, ok := e.domain.(string)
+			if !ok {
+				return nil, errors.New("Firebase RTDB event has no domain")
+			}
+			if domain != "firebaseio.com" {
+				location = strings.SplitN(domain, ".", 2)[0]
+			}
+		}
+		i := strings.Index(name, "/refs/")
+		if i < 0 || !strings.HasPrefix(name, "projects/_/instances/") {
+			return nil, fmt.Errorf("unexpected Firebase RTDB resource %q", name)
+		}
+		instance := strings.TrimPrefix(name[:i], "projects/_/instances/")
+		source = fmt.Sprintf("projects/_/locations/%s/instances/%s", location, instance)
+		subject = name[i+1:]
+	case authService:
+		user, ok := e.data.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("Firebase Auth event data is not an object")
+		}
+		user = copyMap(user)
+		if uid, ok := user["uid"].(string); ok {
+			subject = "users/" + uid
+		}
+		if metadata, ok := user["metadata"].(map[string]interface{}); ok {
+			user["metadata"] = renameKeys(metadata, authMetadataNames)
+		}
+		data = user
+	case analyticsService:
+		if i := strings.Index(name, "/events/"); i >= 0 {
+			appID, err := analyticsAppID(e.data)
+			if err != nil {
+				return nil, err
+			}
+			source, subject = name[:i]+"/apps/"+appID, name[i+1:]
+		}
+	}
+
+	ce := cloudevents.NewEvent()
+	ce.SetID(e.id)
+	ce.SetSource(fmt.Sprintf("//%s/%s", service, source))
+	ce.SetType(ceType)
+	ce.SetTime(e.timestamp)
+	if subject != "" && !faults.has(FaultSubject) {
+		ce.SetSubject(subject)
+	}
+	if err := ce.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("setting CloudEvent data: %v", err)
+	}
+	return &ce, nil
+}
+
+func analyticsAppID(data interface{}) (string, error) {
+	d, _ := data.(map[string]interface{})
+	userDim, _ := d["userDim"].(map[string]interface{})
+	appInfo, _ := userDim["appInfo"].(map[string]interface{})
+	appID, ok := appInfo["appId"].(string)
+	if !ok {
+		return "", errors.New("Firebase Analytics event data has no userDim.appInfo.appId")
+	}
+	return appID, nil
+}
+
+// cloudEventToLegacy converts a CloudEvent to the "event, context" representation as described
+// in docs/mapping.md.
+func cloudEventToLegacy(ce *cloudevents.Event, faults faultSet) (*legacyEvent, error) {
+	var eventType string
+	for legacyType, ceType := range cloudEventTypes {
+		// Prefer the legacy type that does not start with providers/.
+		if ceType == ce.Type() && (eventType == "" || strings.HasPrefix(eventType, "providers/")) {
+			eventType = legacyType
+		}
+	}
+	if eventType == "" {
+		return nil, fmt.Errorf("unknown CloudEvent type %q", ce.Type())
+	}
+
+	source := strings.TrimPrefix(ce.Source(), "//")
+	parts := strings.SplitN(source, "/", 2)
+	if source == ce.Source() || len(parts) != 2 {
+		return nil, fmt.Errorf("unexpected CloudEvent source %q", ce.Source())
+	}
+	service, name := parts[0], parts[1]
+
+	var data interface{}
+	if len(ce.Data()) > 0 {
+		if err := decodeJSON(ce.Data(), &data); err != nil {
+			return nil, fmt.Errorf("parsing CloudEvent data: %v", err)
+		}
+	}
+
+	appendSubject := true
+	switch service {
+	case pubSubService:
+		d, _ := data.(map[string]interface{})
+		message, ok := d["message"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("Pub/Sub CloudEvent data has no message")
+		}
+		message = copyMap(message)
+		delete(message, "messageId")
+		delete(message, "publishTime")
+		data = message
+	case databaseService:
+		// Remove the location, which is not part of the legacy resource.
+		if i := strings.Index(name, "/locations/"); i >= 0 {
+			rest := name[i+len("/locations/"):]
+			if j := strings.Index(rest, "/"); j >= 0 {
+				name = name[:i] + rest[j:]
+			}
+		}
+	case authService:
+		appendSubject = false
+		if user, ok := data.(map[string]interface{}); ok {
+			user = copyMap(user)
+			if metadata, ok := user["metadata"].(map[string]interface{}); ok {
+				user["metadata"] = renameKeys(metadata, reverse(authMetadataNames))
+			}
+			data = user
+		}
+	case analyticsService:
+		name = strings.SplitN(name, "/apps/", 2)[0]
+	}
+	if appendSubject && ce.Subject() != "" && !faults.has(FaultResource) {
+		name = name + "/" + ce.Subject()
+	}
+
+	var resource interface{} = name
+	if t, ok := legacyResourceTypes[service]; ok {
+		resource = map[string]interface{}{
+			"service": service,
+			"name":    name,
+			"type":    t,
+		}
+	}
+	return &legacyEvent{
+		id:        ce.ID(),
+		timestamp: ce.Time(),
+		eventType: eventType,
+		resource:  resource,
+		data:      data,
+	}, nil
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func renameKeys(m map[string]interface{}, names map[string]string) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if name, ok := names[k]; ok {
+			k = name
+		}
+		c[k] = v
+	}
+	return c
+}
+
+func reverse(m map[string]string) map[string]string {
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[v] = k
+	}
+	return r
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reference implements a minimal Functions Framework that conforms to docs/mapping.md,
+// with functions that write what they receive as the conformance tests expect. Deliberate faults
+// can be injected to check that the validators catch them, so that the conformance test client
+// can be tested end to end without building a real framework.
+package reference
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// Fault is a deliberate deviation from the Functions Framework contract.
+type Fault string
+
+const (
+	// FaultHTTPBody makes HTTP functions write only part of the request body.
+	FaultHTTPBody Fault = "http-body"
+	// FaultTypedPayload makes typed functions respond without the payload field.
+	FaultTypedPayload Fault = "typed-payload"
+	// FaultStructuredMode rejects CloudEvents sent in the structured HTTP content mode.
+	FaultStructuredMode Fault = "structured-mode"
+	// FaultStructuredNoOutput acknowledges CloudEvents sent in the structured HTTP content mode
+	// without invoking the function.
+	FaultStructuredNoOutput Fault = "structured-no-output"
+	// FaultExtensions drops the extension attributes of CloudEvents.
+	FaultExtensions Fault = "extensions"
+	// FaultSubject omits the subject when converting legacy events to CloudEvents.
+	FaultSubject Fault = "subject"
+	// FaultPubSubMessage omits messageId and publishTime when converting legacy Pub/Sub events
+	// to CloudEvents.
+	FaultPubSubMessage Fault = "pubsub-message"
+	// FaultRTDBLocation ignores the domain of Firebase RTDB events and always uses the
+	// us-central1 location.
+	FaultRTDBLocation Fault = "rtdb-location"
+	// FaultResource omits the subject from the resource when converting CloudEvents to legacy
+	// events.
+	FaultResource Fault = "resource"
+	// FaultAcceptInvalid invokes the function with malformed or unsupported events instead of
+	// rejecting them.
+	FaultAcceptInvalid Fault = "accept-invalid"
+	// FaultSerial handles one request at a time.
+	FaultSerial Fault = "serial"
+)
+
+// Faults returns all faults, sorted by name.
+func Faults() []Fault {
+	faults := []Fault{
+		FaultHTTPBody,
+		FaultTypedPayload,
+		FaultStructuredMode,
+		FaultStructuredNoOutput,
+		FaultExtensions,
+		FaultSubject,
+		FaultPubSubMessage,
+		FaultRTDBLocation,
+		FaultResource,
+		FaultAcceptInvalid,
+		FaultSerial,
+	}
+	sort.Slice(faults, func(i, j int) bool { return faults[i] < faults[j] })
+	return faults
+}
+
+// ParseFaults parses a comma separated list of faults.
+func ParseFaults(s string) ([]Fault, error) {
+	known := map[Fault]bool{}
+	for _, f := range Faults() {
+		known[f] = true
+	}
+	var faults []Fault
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[Fault(name)] {
+			return nil, fmt.Errorf("unknown fault %q, want one of %v", name, Faults())
+		}
+		faults = append(faults, Fault(name))
+	}
+	return faults, nil
+}
+
+type faultSet map[Fault]bool
+
+func (s faultSet) has(f Fault) bool {
+	return s[f]
+}
+
+// Options configures the reference server.
+type Options struct {
+	// Signature is the declarative signature of the function, i.e. "http", "typed", "cloudevent"
+	// or "legacyevent".
+	Signature string
+	// Faults are the faults to inject.
+	Faults []Fault
+	// Delay is how long the function runs for, e.g. to validate concurrency.
+	Delay time.Duration
+}
+
+type server struct {
+	signature string
+	faults    faultSet
+	delay     time.Duration
+	output    func([]byte)
+
+	// serial is held while handling a request if FaultSerial is injected.
+	serial sync.Mutex
+}
+
+// NewHandler returns a handler that serves a function with the given options. The function passes
+// what it receives to output, in the format the conformance tests expect in the function output
+// file.
+func NewHandler(opts Options, output func([]byte)) (http.Handler, error) {
+	switch opts.Signature {
+	case "http", "typed", "cloudevent", "legacyevent":
+	default:
+		return nil, fmt.Errorf("unsupported signature %q, want one of 'http', 'typed', 'cloudevent' or 'legacyevent'", opts.Signature)
+	}
+	s := &server{
+		signature: opts.Signature,
+		faults:    faultSet{},
+		delay:     opts.Delay,
+		output:    output,
+	}
+	for _, f := range opts.Faults {
+		s.faults[f] = true
+	}
+	return s, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.faults.has(FaultSerial) {
+		s.serial.Lock()
+		defer s.serial.Unlock()
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("reading request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if s.faults.has(FaultStructuredNoOutput) && isStructured(r) {
+		return
+	}
+
+	var output, response []byte
+	switch s.signature {
+	case "http":
+		output = body
+		if s.faults.has(FaultHTTPBody) {
+			output = body[:len(body)/2]
+		}
+	case "typed":
+		output, response, err = s.typed(body)
+	case "cloudevent":
+		output, err = s.cloudEvent(r, body)
+	case "legacyevent":
+		output, err = s.legacyEvent(r, body)
+	}
+	if err != nil {
+		if !s.faults.has(FaultAcceptInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		output = body
+	}
+
+	time.Sleep(s.delay)
+	s.output(output)
+	if response != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+	}
+}
+
+// typed echoes the request object in the payload field of the response.
+func (s *server) typed(body []byte) ([]byte, []byte, error) {
+	var req interface{}
+	if err := decodeJSON(body, &req); err != nil {
+		return nil, nil, fmt.Errorf("parsing request: %v", err)
+	}
+	field := "payload"
+	if s.faults.has(FaultTypedPayload) {
+		field = "request"
+	}
+	res, err := json.Marshal(map[string]interface{}{field: req})
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshalling response: %v", err)
+	}
+	return body, res, nil
+}
+
+// cloudEvent returns the CloudEvent the function receives, converting legacy events.
+func (s *server) cloudEvent(r *http.Request, body []byte) ([]byte, error) {
+	var ce *cloudevents.Event
+	if isCloudEvent(r) {
+		var err error
+		if ce, err = s.readCloudEvent(r, body); err != nil {
+			return nil, err
+		}
+	} else {
+		e, err := parseLegacyEvent(body)
+		if err != nil {
+			return nil, err
+		}
+		if ce, err = e.toCloudEvent(s.faults); err != nil {
+			return nil, err
+		}
+	}
+	if s.faults.has(FaultExtensions) {
+		for name := range ce.Extensions() {
+			ce.SetExtension(name, nil)
+		}
+	}
+	return json.Marshal(ce)
+}
+
+// legacyEvent returns the event and context the function receives, converting CloudEvents.
+func (s *server) legacyEvent(r *http.Request, body []byte) ([]byte, error) {
+	var e *legacyEvent
+	var err error
+	if isCloudEvent(r) {
+		ce, err := s.readCloudEvent(r, body)
+		if err != nil {
+			return nil, err
+		}
+		if e, err = cloudEventToLegacy(ce, s.faults); err != nil {
+			return nil, err
+		}
+	} else if e, err = parseLegacyEvent(body); err != nil {
+		return nil, err
+	}
+	return json.Marshal(e.output())
+}
+
+func isCloudEvent(r *http.Request) bool {
+	return r.Header.Get("ce-specversion") != "" || isStructured(r)
+}
+
+func isStructured(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/cloudevents+json")
+}
+
+func (s *server) readCloudEvent(r *http.Request, body []byte) (*cloudevents.Event, error) {
+	if s.faults.has(FaultStructuredMode) && isStructured(r) {
+		return nil, fmt.Errorf("structured CloudEvents are not supported")
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	ce, err := cloudevents.NewEventFromHTTPRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing CloudEvent: %v", err)
+	}
+	if err := ce.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CloudEvent: %v", err)
+	}
+	return ce, nil
+}
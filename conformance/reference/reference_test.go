@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/conformance"
+)
+
+// validate validates the reference server with the given options.
+func validate(t *testing.T, opts Options, params conformance.ValidatorParams) error {
+	t.Helper()
+	params.FunctionServer = conformance.NewHandlerServer(func(output func([]byte)) http.Handler {
+		h, err := NewHandler(opts, output)
+		if err != nil {
+			t.Fatalf("NewHandler: %v", err)
+		}
+		return h
+	})
+	params.FunctionSignature = opts.Signature
+	params.DeclarativeSignature = opts.Signature
+	v := conformance.NewValidator(params)
+	shutdown, err := v.Start()
+	if err != nil {
+		t.Fatalf("starting reference server: %v", err)
+	}
+	defer shutdown()
+	return v.Validate(v.URL())
+}
+
+func TestReferenceConforms(t *testing.T) {
+	for _, signature := range []string{"http", "typed", "cloudevent", "legacyevent"} {
+		t.Run(signature, func(t *testing.T) {
+			err := validate(t, Options{Signature: signature}, conformance.ValidatorParams{
+				ValidateMapping: true,
+				ValidateInvalid: true,
+				FuzzCount:       20,
+				FuzzSeed:        1,
+			})
+			if err != nil {
+				t.Errorf("validating reference server: %v", err)
+			}
+		})
+	}
+}
+
+// TestFaultsAreCaught checks that each fault fails the validation that covers it.
+func TestFaultsAreCaught(t *testing.T) {
+	testCases := []struct {
+		fault     Fault
+		signature string
+		params    conformance.ValidatorParams
+		delay     time.Duration
+	}{
+		{
+			fault:     FaultHTTPBody,
+			signature: "http",
+		},
+		{
+			fault:     FaultTypedPayload,
+			signature: "typed",
+		},
+		{
+			fault:     FaultStructuredMode,
+			signature: "cloudevent",
+		},
+		{
+			fault:     FaultStructuredNoOutput,
+			signature: "cloudevent",
+		},
+		{
+			fault:     FaultExtensions,
+			signature: "cloudevent",
+		},
+		{
+			fault:     FaultSubject,
+			signature: "cloudevent",
+			params:    conformance.ValidatorParams{ValidateMapping: true},
+		},
+		{
+			fault:     FaultPubSubMessage,
+			signature: "cloudevent",
+			params:    conformance.ValidatorParams{ValidateMapping: true},
+		},
+		{
+			fault:     FaultRTDBLocation,
+			signature: "cloudevent",
+			params:    conformance.ValidatorParams{ValidateMapping: true},
+		},
+		{
+			fault:     FaultResource,
+			signature: "legacyevent",
+			params:    conformance.ValidatorParams{ValidateMapping: true},
+		},
+		{
+			fault:     FaultAcceptInvalid,
+			signature: "cloudevent",
+			params:    conformance.ValidatorParams{ValidateInvalid: true},
+		},
+		{
+			fault:     FaultSerial,
+			signature: "http",
+			params:    conformance.ValidatorParams{ValidateConcurrency: true},
+			delay:     time.Second,
+		},
+	}
+
+	covered := map[Fault]bool{}
+	for _, tc := range testCases {
+		covered[tc.fault] = true
+		t.Run(string(tc.fault), func(t *testing.T) {
+			if tc.delay > 0 && testing.Short() {
+				t.Skip("skipping slow validation in short mode")
+			}
+			opts := Options{Signature: tc.signature, Delay: tc.delay}
+			if err := validate(t, opts, tc.params); err != nil {
+				t.Fatalf("validating reference server without fault: %v", err)
+			}
+			opts.Faults = []Fault{tc.fault}
+			if err := validate(t, opts, tc.params); err == nil {
+				t.Errorf("validating reference server with fault %q got nil error, want error", tc.fault)
+			}
+		})
+	}
+	for _, f := range Faults() {
+		if !covered[f] {
+			t.Errorf("fault %q is not covered by a test case", f)
+		}
+	}
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults("subject, serial,")
+	if err != nil {
+		t.Fatalf("ParseFaults: %v", err)
+	}
+	if len(faults) != 2 || faults[0] != FaultSubject || faults[1] != FaultSerial {
+		t.Errorf("ParseFaults() = %v, want [%s %s]", faults, FaultSubject, FaultSerial)
+	}
+	if _, err := ParseFaults("unknown"); err == nil {
+		t.Errorf("ParseFaults(%q) got nil error, want error", "unknown")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+)
+
+const (
+	dockerRuntimeName = "docker"
+	podmanRuntimeName = "podman"
+
+	// functionPort is the port function servers listen on, see DefaultURL.
+	functionPort = "8080"
+)
+
+// containerRuntime is a container engine with a Docker compatible Engine API, which function
+// containers are built and run with.
+type containerRuntime interface {
+	// name returns the name of the runtime, "docker" or "podman".
+	name() string
+	// host returns the address of the Engine API, e.g. "unix:///var/run/docker.sock".
+	host() string
+	// buildHost returns the Engine API address to give to the buildpacks lifecycle, or "" for the
+	// default Docker socket.
+	buildHost() string
+	// network configures a container so that its function server is reachable at DefaultURL.
+	network(config *container.Config, hostConfig *container.HostConfig)
+}
+
+// dockerRuntime runs containers with Docker, using the network of the host.
+type dockerRuntime struct {
+	addr string
+}
+
+func (r dockerRuntime) name() string {
+	return dockerRuntimeName
+}
+
+func (r dockerRuntime) host() string {
+	return r.addr
+}
+
+func (r dockerRuntime) buildHost() string {
+	return ""
+}
+
+func (r dockerRuntime) network(config *container.Config, hostConfig *container.HostConfig) {
+	hostConfig.NetworkMode = "host"
+}
+
+// podmanRuntime runs containers with Podman. Rootless Podman cannot always share the network of
+// the host, so the function port is published on the loopback interface instead.
+type podmanRuntime struct {
+	addr string
+}
+
+func (r podmanRuntime) name() string {
+	return podmanRuntimeName
+}
+
+func (r podmanRuntime) host() string {
+	return r.addr
+}
+
+func (r podmanRuntime) buildHost() string {
+	// The lifecycle mounts the socket to export the image, which is not at the Docker path.
+	return r.addr
+}
+
+func (r podmanRuntime) network(config *container.Config, hostConfig *container.HostConfig) {
+	port := network.MustParsePort(functionPort + "/tcp")
+	config.ExposedPorts = network.PortSet{port: struct{}{}}
+	hostConfig.PortBindings = network.PortMap{
+		port: {{HostIP: netip.MustParseAddr("127.0.0.1"), HostPort: functionPort}},
+	}
+}
+
+// newClient returns an Engine API client for the runtime. TLS and API version settings are taken
+// from the environment as with the docker CLI.
+func newClient(r containerRuntime) (*client.Client, error) {
+	return client.New(client.FromEnv, client.WithHost(r.host()))
+}
+
+// newContainerRuntime returns the container runtime with the given name, or detects the runtime
+// from the environment and the available sockets if the name is empty.
+func newContainerRuntime(name string) (containerRuntime, error) {
+	return selectContainerRuntime(name, os.Getenv, exists)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func selectContainerRuntime(name string, getenv func(string) string, exists func(string) bool) (containerRuntime, error) {
+	dockerHost := getenv(client.EnvOverrideHost)
+	podmanHost := getenv("CONTAINER_HOST")
+	if podmanHost == "" {
+		for _, socket := range podmanSockets(getenv) {
+			if exists(socket) {
+				podmanHost = "unix://" + socket
+				break
+			}
+		}
+	}
+
+	switch name {
+	case dockerRuntimeName:
+		if dockerHost == "" {
+			dockerHost = client.DefaultDockerHost
+		}
+		return dockerRuntime{addr: dockerHost}, nil
+	case podmanRuntimeName:
+		if podmanHost == "" {
+			return nil, fmt.Errorf("no Podman socket found at %s, start one with 'systemctl --user start podman.socket' or set CONTAINER_HOST", strings.Join(podmanSockets(getenv), " or "))
+		}
+		return podmanRuntime{addr: podmanHost}, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q, want %q or %q", name, dockerRuntimeName, podmanRuntimeName)
+	}
+
+	// DOCKER_HOST is commonly pointed at the Podman socket for tools that only know Docker.
+	if dockerHost != "" {
+		if strings.Contains(dockerHost, podmanRuntimeName) {
+			return podmanRuntime{addr: dockerHost}, nil
+		}
+		return dockerRuntime{addr: dockerHost}, nil
+	}
+	if exists(strings.TrimPrefix(client.DefaultDockerHost, "unix://")) || podmanHost == "" {
+		return dockerRuntime{addr: client.DefaultDockerHost}, nil
+	}
+	return podmanRuntime{addr: podmanHost}, nil
+}
+
+// podmanSockets returns the paths of the rootless and the rootful Podman sockets.
+func podmanSockets(getenv func(string) string) []string {
+	runtimeDir := getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return []string{
+		filepath.Join(runtimeDir, "podman", "podman.sock"),
+		"/run/podman/podman.sock",
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+)
+
+func TestSelectContainerRuntime(t *testing.T) {
+	const (
+		dockerSocket = "/var/run/docker.sock"
+		podmanSocket = "/run/user/1000/podman/podman.sock"
+	)
+	testCases := []struct {
+		name     string
+		runtime  string
+		env      map[string]string
+		sockets  []string
+		wantName string
+		wantHost string
+		wantErr  bool
+	}{
+		{
+			name:     "defaults to docker",
+			wantName: "docker",
+			wantHost: "unix://" + dockerSocket,
+		},
+		{
+			name:     "detects docker socket",
+			sockets:  []string{dockerSocket, podmanSocket},
+			wantName: "docker",
+			wantHost: "unix://" + dockerSocket,
+		},
+		{
+			name:     "detects rootless podman socket",
+			sockets:  []string{podmanSocket},
+			wantName: "podman",
+			wantHost: "unix://" + podmanSocket,
+		},
+		{
+			name:     "detects podman from DOCKER_HOST",
+			env:      map[string]string{"DOCKER_HOST": "unix://" + podmanSocket},
+			sockets:  []string{dockerSocket},
+			wantName: "podman",
+			wantHost: "unix://" + podmanSocket,
+		},
+		{
+			name:     "uses DOCKER_HOST",
+			env:      map[string]string{"DOCKER_HOST": "tcp://docker:2375"},
+			wantName: "docker",
+			wantHost: "tcp://docker:2375",
+		},
+		{
+			name:     "uses CONTAINER_HOST for podman",
+			runtime:  "podman",
+			env:      map[string]string{"CONTAINER_HOST": "unix:///tmp/podman.sock"},
+			wantName: "podman",
+			wantHost: "unix:///tmp/podman.sock",
+		},
+		{
+			name:     "selects docker",
+			runtime:  "docker",
+			sockets:  []string{podmanSocket},
+			wantName: "docker",
+			wantHost: "unix://" + dockerSocket,
+		},
+		{
+			name:     "selects podman",
+			runtime:  "podman",
+			sockets:  []string{dockerSocket, "/run/podman/podman.sock"},
+			wantName: "podman",
+			wantHost: "unix:///run/podman/podman.sock",
+		},
+		{
+			name:    "fails without podman socket",
+			runtime: "podman",
+			sockets: []string{dockerSocket},
+			wantErr: true,
+		},
+		{
+			name:    "fails for unknown runtime",
+			runtime: "containerd",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"}
+			for k, v := range tc.env {
+				env[k] = v
+			}
+			getenv := func(key string) string { return env[key] }
+			exists := func(path string) bool {
+				for _, s := range tc.sockets {
+					if s == path {
+						return true
+					}
+				}
+				return false
+			}
+
+			rt, err := selectContainerRuntime(tc.runtime, getenv, exists)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("selectContainerRuntime(%q) got nil error, want error", tc.runtime)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectContainerRuntime(%q): %v", tc.runtime, err)
+			}
+			if rt.name() != tc.wantName || rt.host() != tc.wantHost {
+				t.Errorf("selectContainerRuntime(%q) = %s at %s, want %s at %s", tc.runtime, rt.name(), rt.host(), tc.wantName, tc.wantHost)
+			}
+		})
+	}
+}
+
+func TestPodmanRuntimePublishesPort(t *testing.T) {
+	config, hostConfig := &container.Config{}, &container.HostConfig{}
+	podmanRuntime{}.network(config, hostConfig)
+
+	port := network.MustParsePort("8080/tcp")
+	if _, ok := config.ExposedPorts[port]; !ok {
+		t.Errorf("exposed ports = %v, want %v", config.ExposedPorts, port)
+	}
+	bindings := hostConfig.PortBindings[port]
+	if len(bindings) != 1 || bindings[0].HostPort != "8080" || bindings[0].HostIP.String() != "127.0.0.1" {
+		t.Errorf("port bindings = %v, want 127.0.0.1:8080", bindings)
+	}
+	if hostConfig.NetworkMode.IsHost() {
+		t.Errorf("network mode = %q, want port publishing", hostConfig.NetworkMode)
+	}
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package conformance
 
 import (
 	"bytes"
@@ -32,12 +32,41 @@ var (
 	defaultStderrFile = path.Join(os.TempDir(), "/ff_serverlog_stderr.txt")
 )
 
-type functionServer interface {
+// FunctionServer runs a function in a Functions Framework server.
+type FunctionServer interface {
+	// Start starts the server, writing its logs to stdoutFile and stderrFile. The function writes
+	// what it receives to functionOutputFile. The returned function shuts the server down.
 	Start(stdoutFile, stderrFile, functionOutputFile string) (func(), error)
+	// OutputFile returns the contents of the function output file.
 	OutputFile() ([]byte, error)
+	// ClearOutputFile removes the function output file, if it exists.
+	ClearOutputFile() error
+}
+
+// ceEncoding is the HTTP content mode used to send a CloudEvent.
+type ceEncoding int
+
+const (
+	binaryEncoding ceEncoding = iota
+	structuredEncoding
+)
+
+func (e ceEncoding) String() string {
+	switch e {
+	case binaryEncoding:
+		return "binary"
+	case structuredEncoding:
+		return "structured"
+	}
+	return ""
 }
 
 func send(url string, t events.EventType, data []byte) error {
+	return sendWithEncoding(url, t, data, binaryEncoding)
+}
+
+// sendWithEncoding sends an event, using the given content mode if it is a CloudEvent.
+func sendWithEncoding(url string, t events.EventType, data []byte, enc ceEncoding) error {
 	switch t {
 	case events.LegacyEvent:
 		_, err := sendHTTP(url, data)
@@ -47,7 +76,7 @@ func send(url string, t events.EventType, data []byte) error {
 		if err != nil {
 			return fmt.Errorf("building cloudevent: %v", err)
 		}
-		return sendCE(url, *ce)
+		return sendCE(url, *ce, enc)
 	}
 	return nil
 }
@@ -67,8 +96,27 @@ func sendHTTP(url string, data []byte) ([]byte, error) {
 	return body, nil
 }
 
-func sendCE(url string, e cloudevents.Event) error {
+// sendInvalid sends a malformed event as-is, without parsing it first, and
+// returns the HTTP status code of the response. CloudEvents are sent in
+// structured mode so that invalid attributes reach the framework unchanged.
+func sendInvalid(url string, t events.EventType, data []byte) (int, error) {
+	contentType := "application/json"
+	if t == events.CloudEvent {
+		contentType = "application/cloudevents+json"
+	}
+	resp, err := http.Post(url, contentType, bytes.NewBuffer(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to send HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func sendCE(url string, e cloudevents.Event, enc ceEncoding) error {
 	ctx := cloudevents.ContextWithTarget(context.Background(), url)
+	if enc == structuredEncoding {
+		ctx = cloudevents.WithEncodingStructured(ctx)
+	}
 
 	p, err := cloudevents.NewHTTP()
 	if err != nil {
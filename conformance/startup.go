@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+const (
+	// nonexistentTarget is a function target that no function is expected to have.
+	nonexistentTarget = "conformanceNonexistentTarget"
+	// unknownSignatureType is a signature type that no framework is expected to support.
+	unknownSignatureType = "conformanceUnknownType"
+)
+
+// startupCase is a misconfiguration of the function target or signature type, which a Functions
+// Framework must reject by exiting with a non-zero status and telling what is wrong on stderr.
+type startupCase struct {
+	// envs are set in addition to the environment of the server.
+	envs []string
+	// unsetEnvs are removed from the environment of the server.
+	unsetEnvs []string
+	// args are appended to the command of the server.
+	args []string
+	// wantStderr is what stderr must mention, ignoring case.
+	wantStderr string
+	// skippedReason is set if the case cannot be run with the configuration of the validator.
+	skippedReason string
+}
+
+// startupCases returns the startup cases by name.
+func (v *Validator) startupCases() map[string]startupCase {
+	signatureType := v.functionSignature
+	// Without a valid target, the server would exit because of the target instead.
+	unknownSignature := startupCase{
+		envs:       []string{"FUNCTION_TARGET=" + v.target, "FUNCTION_SIGNATURE_TYPE=" + unknownSignatureType},
+		wantStderr: unknownSignatureType,
+	}
+	// The flag can only be shown to override a valid target set in the environment.
+	flagOverride := startupCase{
+		envs:       []string{"FUNCTION_TARGET=" + v.target},
+		args:       []string{v.startupTargetFlag, nonexistentTarget},
+		wantStderr: nonexistentTarget,
+	}
+	if v.target == "" {
+		unknownSignature = startupCase{skippedReason: "no valid function target is configured"}
+		flagOverride = unknownSignature
+	} else if v.startupTargetFlag == "" {
+		flagOverride = startupCase{skippedReason: "no target flag of the framework is configured"}
+	}
+	return map[string]startupCase{
+		"missing-target": {
+			envs:       []string{"FUNCTION_SIGNATURE_TYPE=" + signatureType},
+			unsetEnvs:  []string{"FUNCTION_TARGET"},
+			wantStderr: "target",
+		},
+		"nonexistent-target": {
+			envs:       []string{"FUNCTION_TARGET=" + nonexistentTarget, "FUNCTION_SIGNATURE_TYPE=" + signatureType},
+			wantStderr: nonexistentTarget,
+		},
+		"unknown-signature-type":    unknownSignature,
+		"target-flag-overrides-env": flagOverride,
+	}
+}
+
+// StartupCaseNames returns the names of the startup cases, which validate that the framework
+// rejects a missing or nonexistent function target or an unknown signature type when it starts.
+func (v *Validator) StartupCaseNames() []string {
+	if !v.validateStartup {
+		return nil
+	}
+	return []string{"missing-target", "nonexistent-target", "unknown-signature-type", "target-flag-overrides-env"}
+}
+
+// ValidateStartupCase runs the function server with the misconfiguration of a startup case and
+// checks that it exits during startup with a non-zero status, mentioning what is wrong on stderr.
+// The server must be run with RunCmd and not be running. The returned error is only set if the
+// server could not be run.
+func (v *Validator) ValidateStartupCase(name string) (*events.ValidationInfo, error) {
+	c, ok := v.startupCases()[name]
+	if !ok {
+		return nil, fmt.Errorf("no startup case %q", name)
+	}
+	vi := &events.ValidationInfo{Name: name}
+	if c.skippedReason != "" {
+		vi.SkippedReason = c.skippedReason
+		return vi, nil
+	}
+	l, ok := v.funcServer.(*localFunctionServer)
+	if !ok {
+		return nil, fmt.Errorf("startup cases require a server run with RunCmd")
+	}
+
+	logs := newServerLogs(v.logs.stream)
+	logs.setStep("startup %q", name)
+	s := &localFunctionServer{
+		cmd:        l.cmd,
+		envs:       append(append([]string{}, l.envs...), c.envs...),
+		unsetEnvs:  c.unsetEnvs,
+		args:       c.args,
+		startDelay: l.startDelay,
+		logs:       logs,
+	}
+	stderrFile := startupLogFile(v.stderrFile, name)
+	shutdown, err := s.Start(startupLogFile(v.stdoutFile, name), stderrFile, v.functionOutputFile)
+	if err == nil {
+		shutdown()
+		vi.Errs = append(vi.Errs, fmt.Errorf("expected the framework to exit during startup, but it was still running after %v", l.startDelay))
+		return vi, nil
+	}
+	var exitErr *serverExitError
+	if !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("running function server for %q: %v", name, err)
+	}
+	if exitErr.code == 0 {
+		vi.Errs = append(vi.Errs, fmt.Errorf("expected the framework to exit with a non-zero status, got %s", exitErr.status))
+	}
+	stderr, err := ioutil.ReadFile(stderrFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading stderr of function server for %q: %v", name, err)
+	}
+	if !strings.Contains(strings.ToLower(string(stderr)), strings.ToLower(c.wantStderr)) {
+		vi.Errs = append(vi.Errs, fmt.Errorf("expected stderr to mention %q to tell what is wrong, see %s", c.wantStderr, stderrFile))
+	}
+	return vi, nil
+}
+
+// ValidateStartupCases validates every startup case.
+func (v *Validator) ValidateStartupCases() ([]*events.ValidationInfo, error) {
+	vis := []*events.ValidationInfo{}
+	for _, name := range v.StartupCaseNames() {
+		vi, err := v.ValidateStartupCase(name)
+		if err != nil {
+			return nil, err
+		}
+		vis = append(vis, vi)
+	}
+	return vis, nil
+}
+
+// startupLogFile returns the name of the log file of a startup case, next to the log file of the
+// validated server.
+func startupLogFile(file, name string) string {
+	return fmt.Sprintf("%s_startup_%s.txt", strings.TrimSuffix(file, ".txt"), name)
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// frameworkProgram resolves its function target and signature type like a Functions Framework.
+const frameworkProgram = `package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	target := os.Getenv("FUNCTION_TARGET")
+	flag.StringVar(&target, "target", target, "function target")
+	flag.Parse()
+	signatureType := os.Getenv("FUNCTION_SIGNATURE_TYPE")
+	switch {
+	case target == "":
+		fail("no function target, set FUNCTION_TARGET or --target")
+	case target != "Func":
+		fail("function target %q not found", target)
+	case signatureType != "http":
+		fail("unsupported signature type %q", signatureType)
+	}
+	time.Sleep(90 * time.Second)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+`
+
+// ignoringProgram starts whatever its function target and signature type.
+const ignoringProgram = `package main
+
+import "time"
+
+func main() {
+	time.Sleep(90 * time.Second)
+}
+`
+
+func TestValidateStartupCases(t *testing.T) {
+	testCases := []struct {
+		name       string
+		program    string
+		target     string
+		targetFlag string
+		wantErrs   bool
+		// wantSkipped are the names of the cases that are skipped.
+		wantSkipped []string
+	}{
+		{
+			name:       "conforming framework",
+			program:    frameworkProgram,
+			target:     "Func",
+			targetFlag: "--target",
+		},
+		{
+			name:        "without target flag",
+			program:     frameworkProgram,
+			target:      "Func",
+			wantSkipped: []string{"target-flag-overrides-env"},
+		},
+		{
+			name:        "without target",
+			program:     frameworkProgram,
+			targetFlag:  "--target",
+			wantSkipped: []string{"unknown-signature-type", "target-flag-overrides-env"},
+		},
+		{
+			name:       "framework ignoring misconfiguration",
+			program:    ignoringProgram,
+			target:     "Func",
+			targetFlag: "--target",
+			wantErrs:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "main.go")
+			if err := ioutil.WriteFile(src, []byte(tc.program), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			bin := filepath.Join(dir, "framework")
+			if out, err := exec.Command("go", "build", "-o", bin, src).CombinedOutput(); err != nil {
+				t.Fatalf("Failed to build program: %v\n%s", err, out)
+			}
+
+			v := NewValidator(ValidatorParams{
+				RunCmd:            bin,
+				StartDelay:        time.Second,
+				Target:            tc.target,
+				FunctionSignature: "http",
+				Envs:              []string{"FUNCTION_SIGNATURE_TYPE=http"},
+				ValidateStartup:   true,
+				StartupTargetFlag: tc.targetFlag,
+			})
+			v.stdoutFile = filepath.Join(dir, "stdout.txt")
+			v.stderrFile = filepath.Join(dir, "stderr.txt")
+			vis, err := v.ValidateStartupCases()
+			if err != nil {
+				t.Fatalf("ValidateStartupCases() got error: %v", err)
+			}
+			if len(vis) != len(v.StartupCaseNames()) {
+				t.Fatalf("ValidateStartupCases() got %d results, want %d", len(vis), len(v.StartupCaseNames()))
+			}
+			for _, vi := range vis {
+				skip := false
+				for _, name := range tc.wantSkipped {
+					skip = skip || vi.Name == name
+				}
+				if got := vi.SkippedReason != ""; got != skip {
+					t.Errorf("%s skipped = %v, want %v", vi.Name, got, skip)
+				}
+				if got := vi.Errs != nil; got != (tc.wantErrs && !skip) {
+					t.Errorf("%s got errors %v, want errors = %v", vi.Name, vi.Errs, tc.wantErrs)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveEnvs(t *testing.T) {
+	got := removeEnvs([]string{"FUNCTION_TARGET=Func", "PORT=8080", "FUNCTION_TARGET_X=1", "FUNCTION_TARGET="}, []string{"FUNCTION_TARGET"})
+	want := []string{"PORT=8080", "FUNCTION_TARGET_X=1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("removeEnvs() = %v, want %v", got, want)
+	}
+}
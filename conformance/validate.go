@@ -0,0 +1,581 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance validates that a Functions Framework server behaves as specified, e.g. that
+// it converts events as described in docs/mapping.md. It is used by the conformance test client
+// and can be used directly from Go tests; see the conformancetest package.
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+	"github.com/google/go-cmp/cmp"
+)
+
+// DefaultURL is the URL at which function servers are expected to listen.
+const DefaultURL = "http://localhost:8080"
+
+// defaultStartDelay is the time to wait for the server to start if none is set. Without it, a
+// server that fails to start would not have exited yet when it is checked.
+const defaultStartDelay = time.Second
+
+// ValidatorParams configures a Validator.
+type ValidatorParams struct {
+	// FunctionServer is the server to validate. If nil, the server is run locally with RunCmd, or
+	// built and run with buildpacks if UseBuildpacks is true.
+	FunctionServer FunctionServer
+	UseBuildpacks  bool
+	RunCmd         string
+	// StartDelay is the time to wait for the server to start before sending requests, which is
+	// 1s if it is zero like the default of the client.
+	StartDelay time.Duration
+	// OutputFile is the name of the file the function writes its input to.
+	OutputFile     string
+	Source         string
+	Target         string
+	Runtime        string
+	RuntimeVersion string
+	BuilderURL     string
+	Tag            string
+	// ContainerRuntime is the container runtime used with buildpacks, "docker" or "podman". It
+	// is detected from DOCKER_HOST, CONTAINER_HOST and the available sockets if empty.
+	ContainerRuntime string
+	// Image is a pre-built function image to run instead of building one with buildpacks.
+	Image string
+	// CacheBuilds reuses an image built earlier from the same source, builder and build
+	// environment instead of building it again. Cached images are not removed.
+	CacheBuilds bool
+	// BuildEnvs are additional environment variables for the build with buildpacks, as
+	// KEY=VALUE, or KEY to take the value from the environment. They override the defaults, e.g.
+	// GOOGLE_RUNTIME_VERSION.
+	BuildEnvs []string
+	// Buildpacks are additional buildpacks for the build, in any form accepted by pack.
+	Buildpacks []string
+	// RunImage overrides the run image of the builder.
+	RunImage string
+	// Volumes are mounted into the build containers, as /host/path:/container/path[:ro].
+	Volumes []string
+	// RuntimeProfile emulates the runtime environment of Cloud Functions in the container built
+	// with buildpacks: "gen1" or "gen2". The environment is not emulated if it is empty.
+	RuntimeProfile string
+	// PullPolicy is the policy for pulling the builder, run and pre-built images with buildpacks:
+	// "always" (the default), "if-not-present" or "never", e.g. when offline. A pre-built Image is
+	// only pulled if it is not present with "always".
+	PullPolicy string
+	// KeepImage keeps the image built with buildpacks after the validation, for debugging.
+	KeepImage bool
+	// FunctionSignature is the signature of the function as configured in GCF, i.e. "http",
+	// "cloudevent" or "event", which may also be given as "legacyevent". The server is run with it
+	// as FUNCTION_SIGNATURE_TYPE.
+	FunctionSignature string
+	// DeclarativeSignature is the declarative signature of the function, i.e. "http", "typed",
+	// "cloudevent" or "legacyevent".
+	DeclarativeSignature string
+	ValidateMapping      bool
+	ValidateConcurrency  bool
+	ValidateInvalid      bool
+	// ValidateStartup validates that the framework run with RunCmd rejects a missing or
+	// nonexistent function target or an unknown signature type when it starts. Target is used as
+	// the valid target; the cases that need one are skipped without it.
+	ValidateStartup bool
+	// StartupTargetFlag is the command line flag of the framework that sets the function target,
+	// e.g. "--target", which is validated to take precedence over FUNCTION_TARGET if set.
+	StartupTargetFlag string
+	FuzzCount         int
+	FuzzSeed          int64
+	FuzzExportDir     string
+	// Envs are additional environment variables for the function, as KEY=VALUE. They take
+	// precedence over FUNCTION_SIGNATURE_TYPE.
+	Envs []string
+	// StreamLogs logs what a server run with RunCmd or buildpacks logs as it is written, in
+	// addition to writing it to the log files.
+	StreamLogs bool
+}
+
+// Validator validates a function server.
+type Validator struct {
+	funcServer           FunctionServer
+	validateMapping      bool
+	validateConcurrency  bool
+	validateInvalid      bool
+	validateStartup      bool
+	target               string
+	startupTargetFlag    string
+	fuzzCount            int
+	fuzzSeed             int64
+	fuzzExportDir        string
+	functionSignature    string
+	declarativeSignature string
+	functionOutputFile   string
+	stdoutFile           string
+	stderrFile           string
+	logs                 *serverLogs
+}
+
+// NewValidator returns a Validator for the given parameters.
+func NewValidator(params ValidatorParams) *Validator {
+	if params.FunctionSignature == "legacyevent" {
+		params.FunctionSignature = "event"
+	}
+	if params.StartDelay == 0 {
+		params.StartDelay = defaultStartDelay
+	}
+	v := Validator{
+		funcServer:           params.FunctionServer,
+		validateMapping:      params.ValidateMapping,
+		validateConcurrency:  params.ValidateConcurrency,
+		validateInvalid:      params.ValidateInvalid,
+		validateStartup:      params.ValidateStartup,
+		target:               params.Target,
+		startupTargetFlag:    params.StartupTargetFlag,
+		fuzzCount:            params.FuzzCount,
+		fuzzSeed:             params.FuzzSeed,
+		fuzzExportDir:        params.FuzzExportDir,
+		functionSignature:    params.FunctionSignature,
+		declarativeSignature: params.DeclarativeSignature,
+		functionOutputFile:   params.OutputFile,
+		stdoutFile:           defaultStdoutFile,
+		stderrFile:           defaultStderrFile,
+		logs:                 newServerLogs(params.StreamLogs),
+	}
+	if v.declarativeSignature == "" {
+		v.declarativeSignature = v.functionSignature
+		if v.declarativeSignature == "event" {
+			v.declarativeSignature = "legacyevent"
+		}
+	}
+	if v.functionOutputFile == "" {
+		v.functionOutputFile = "function_output.json"
+	}
+	if v.funcServer != nil {
+		return &v
+	}
+
+	if !params.UseBuildpacks {
+		// Set runtime env vars that reflect https://cloud.google.com/functions/docs/configuring/env-var
+		var envs []string
+		if params.FunctionSignature != "" {
+			envs = append(envs, "FUNCTION_SIGNATURE_TYPE="+params.FunctionSignature)
+		}
+		v.funcServer = &localFunctionServer{
+			cmd:        params.RunCmd,
+			envs:       append(envs, params.Envs...),
+			startDelay: params.StartDelay,
+			logs:       v.logs,
+		}
+		return &v
+	}
+
+	v.funcServer = &buildpacksFunctionServer{
+		source:               params.Source,
+		target:               params.Target,
+		runtime:              params.Runtime,
+		runtimeVersion:       params.RuntimeVersion,
+		tag:                  params.Tag,
+		funcType:             params.FunctionSignature,
+		envs:                 params.Envs,
+		builderURL:           params.BuilderURL,
+		startDelay:           params.StartDelay,
+		containerRuntimeName: params.ContainerRuntime,
+		keepImage:            params.KeepImage,
+		prebuiltImage:        params.Image,
+		cacheBuilds:          params.CacheBuilds,
+		pullPolicyName:       params.PullPolicy,
+		profileName:          params.RuntimeProfile,
+		logs:                 v.logs,
+		buildEnvs:            params.BuildEnvs,
+		buildpacks:           params.Buildpacks,
+		runImage:             params.RunImage,
+		volumes:              params.Volumes,
+	}
+	return &v
+}
+
+// DeclarativeSignature returns the declarative signature of the validated function.
+func (v *Validator) DeclarativeSignature() string {
+	return v.declarativeSignature
+}
+
+// URL returns the URL at which the function server listens once it is started. It is DefaultURL
+// unless the server has a URL method, like HandlerServer.
+func (v *Validator) URL() string {
+	if s, ok := v.funcServer.(interface{ URL() string }); ok {
+		return s.URL()
+	}
+	return DefaultURL
+}
+
+// FunctionServer returns the server that is validated.
+func (v *Validator) FunctionServer() FunctionServer {
+	return v.funcServer
+}
+
+// Start starts the function server. The returned function shuts it down.
+func (v *Validator) Start() (func(), error) {
+	v.logs.setStep("startup")
+	shutdown, err := v.funcServer.Start(v.stdoutFile, v.stderrFile, v.functionOutputFile)
+	if err != nil {
+		return nil, v.errorWithLogsf("unable to start server: %v", err)
+	}
+	if shutdown == nil {
+		shutdown = func() {}
+	}
+	return shutdown, nil
+}
+
+// Run starts the function server, runs all validations that apply to it and shuts it down.
+func (v *Validator) Run() error {
+	log.Printf("Validating for %s...", v.functionSignature)
+
+	if v.validateStartup {
+		// The startup cases run their own servers, so they run before the validated server starts.
+		log.Printf("Startup validation started...")
+		if err := printResults(v.ValidateStartupCases()); err != nil {
+			return fmt.Errorf("startup validation failure: %v", err)
+		}
+		log.Printf("Startup validation passed!")
+	}
+
+	shutdown, err := v.Start()
+	if err != nil {
+		return err
+	}
+
+	if err := v.Validate(v.URL()); err != nil {
+		// A server that exited fails requests with errors that do not tell why.
+		if exitErr := v.serverExit(); exitErr != nil {
+			err = exitErr
+		}
+		// shutdown to ensure all the logs are flushed
+		shutdown()
+		return v.errorWithLogsf("validation failure: %v", err)
+	}
+
+	shutdown()
+	return nil
+}
+
+func (v *Validator) errorWithLogsf(errorFmt string, paramsFmts ...interface{}) error {
+	logs, readErr := v.readLogs()
+	if readErr != nil {
+		logs = readErr.Error()
+	}
+	return fmt.Errorf("%s\nServer logs: %s", fmt.Sprintf(errorFmt, paramsFmts...), logs)
+}
+
+func (v *Validator) readLogs() (string, error) {
+	stdout, err := ioutil.ReadFile(v.stdoutFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read stdout file %q: %w", v.stdoutFile, err)
+	}
+
+	stderr, err := ioutil.ReadFile(v.stderrFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read stderr file %q: %w", v.stdoutFile, err)
+	}
+
+	return fmt.Sprintf("\n[%s]: '%s'\n[%s]: '%s'", v.stdoutFile, stdout, v.stderrFile, stderr), nil
+}
+
+// ValidateHTTP validates that an HTTP function received a request. The HTTP function should copy
+// the contents of the request into the output file.
+func (v *Validator) ValidateHTTP(url string) error {
+	v.logs.setStep("http")
+	type test struct {
+		Res string `json:"res"`
+	}
+	want := test{Res: "PASS"}
+
+	req, err := json.Marshal(want)
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+
+	if _, err := sendHTTP(url, req); err != nil {
+		return fmt.Errorf("failed to get response from HTTP function: %v", err)
+	}
+
+	output, err := v.funcServer.OutputFile()
+	if err != nil {
+		return fmt.Errorf("reading output file from HTTP function: %v", err)
+	}
+
+	got := test{}
+	if err = json.Unmarshal(output, &got); err != nil {
+		return fmt.Errorf("failed to unmarshal function output JSON: %v, function output: %q", err, output)
+	}
+
+	if !cmp.Equal(got, want) {
+		return fmt.Errorf("unexpected HTTP output data (format does not matter), got: %s, want: %s", output, req)
+	}
+	return nil
+}
+
+// ValidateTyped validates a typed function. The Typed function should echo the request object in
+// the "payload" field of the response.
+func (v *Validator) ValidateTyped(url string) error {
+	v.logs.setStep("typed")
+	type request struct {
+		Message string `json:"message"`
+	}
+
+	req := request{
+		Message: "Hello world!",
+	}
+
+	reqJson, err := json.Marshal(req)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %v", err)
+	}
+
+	body, err := sendHTTP(url, reqJson)
+
+	if err != nil {
+		return fmt.Errorf("failed to get response from HTTP function: %v", err)
+	}
+
+	type response struct {
+		Payload request `json:"payload"`
+	}
+
+	var resJson response
+	if err := json.Unmarshal(body, &resJson); err != nil {
+		return fmt.Errorf("failed to unmarshal function output JSON: %v, function output: %q", err, string(body))
+	}
+
+	if !reflect.DeepEqual(resJson.Payload, req) {
+		return fmt.Errorf("Got response.Payload = %v, wanted %v", resJson.Payload, req)
+	}
+
+	return nil
+}
+
+// EventConversion is a combination of the type of events sent to a function and the type of
+// events the function receives.
+type EventConversion struct {
+	Input  events.EventType
+	Output events.EventType
+}
+
+func (c EventConversion) String() string {
+	if c.Input == c.Output {
+		return c.Input.String()
+	}
+	return fmt.Sprintf("%s to %s", c.Input, c.Output)
+}
+
+// EventConversions returns the event conversions that are validated for the declarative signature.
+func (v *Validator) EventConversions() []EventConversion {
+	var ot events.EventType
+	switch v.declarativeSignature {
+	case "cloudevent":
+		ot = events.CloudEvent
+	case "legacyevent":
+		ot = events.LegacyEvent
+	default:
+		return nil
+	}
+	conversions := []EventConversion{{ot, ot}}
+	if v.validateMapping {
+		it := events.LegacyEvent
+		if ot == events.LegacyEvent {
+			it = events.CloudEvent
+		}
+		conversions = append(conversions, EventConversion{it, ot})
+	}
+	return conversions
+}
+
+// InvalidEventTypes returns the types of invalid events that the function server must reject.
+func (v *Validator) InvalidEventTypes() []events.EventType {
+	if !v.validateInvalid {
+		return nil
+	}
+	switch v.declarativeSignature {
+	case "cloudevent":
+		types := []events.EventType{events.CloudEvent}
+		if v.validateMapping {
+			types = append(types, events.LegacyEvent)
+		}
+		return types
+	case "legacyevent":
+		// Legacy event functions receive legacy events without conversion, so only
+		// malformed CloudEvents are required to be rejected.
+		if v.validateMapping {
+			return []events.EventType{events.CloudEvent}
+		}
+	}
+	return nil
+}
+
+// ValidateEvent sends the input of a test case and validates what the function received. CloudEvent
+// inputs are sent in both HTTP content modes, so there is a result for each of them. The returned
+// error is only set if the event could not be sent or the output could not be read.
+func (v *Validator) ValidateEvent(url, name string, c EventConversion) ([]*events.ValidationInfo, error) {
+	input := events.InputData(name, c.Input)
+	if input == nil {
+		return nil, fmt.Errorf("no input data for event %q", name)
+	}
+
+	// CloudEvents, including their extension attributes, must be received the same way in both
+	// HTTP content modes.
+	encodings := []ceEncoding{binaryEncoding}
+	if c.Input == events.CloudEvent {
+		encodings = append(encodings, structuredEncoding)
+	}
+
+	vis := []*events.ValidationInfo{}
+	for _, enc := range encodings {
+		// The output of the previous mode must not pass for a function that was not invoked.
+		if err := v.funcServer.ClearOutputFile(); err != nil {
+			return nil, fmt.Errorf("clearing output file before %q: %v", name, err)
+		}
+		v.logs.setStep("event %q (%s, %s)", name, c, enc)
+		err := sendWithEncoding(url, c.Input, input, enc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get response from function for %q: %v", name, err)
+		}
+		output, err := v.funcServer.OutputFile()
+		if err != nil {
+			return nil, fmt.Errorf("reading output file from function for %q: %v", name, err)
+		}
+		if vi := events.ValidateEvent(name, c.Input, c.Output, output); vi != nil {
+			if len(encodings) > 1 {
+				vi.Name = fmt.Sprintf("%s (%s)", name, enc)
+			}
+			vis = append(vis, vi)
+		}
+	}
+	return vis, nil
+}
+
+// ValidateEvents validates every test case that has an input of the conversion's input type.
+func (v *Validator) ValidateEvents(url string, c EventConversion) ([]*events.ValidationInfo, error) {
+	eventNames, err := events.EventNames(c.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	vis := []*events.ValidationInfo{}
+	for _, name := range eventNames {
+		nameVIs, err := v.ValidateEvent(url, name, c)
+		if err != nil {
+			return nil, err
+		}
+		vis = append(vis, nameVIs...)
+	}
+	return vis, nil
+}
+
+// ValidateInvalidEvent sends a malformed or unsupported event and checks that the framework
+// rejects it without invoking the function.
+func (v *Validator) ValidateInvalidEvent(url, name string, inputType events.EventType) (*events.ValidationInfo, error) {
+	if err := v.funcServer.ClearOutputFile(); err != nil {
+		return nil, fmt.Errorf("clearing output file before %q: %v", name, err)
+	}
+	v.logs.setStep("invalid %s %q", inputType, name)
+	statusCode, err := sendInvalid(url, inputType, events.InvalidInputData(name, inputType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from function for %q: %v", name, err)
+	}
+	_, err = v.funcServer.OutputFile()
+	return events.ValidateInvalidEvent(name, statusCode, err == nil), nil
+}
+
+// ValidateInvalidEvents validates every invalid event of a type.
+func (v *Validator) ValidateInvalidEvents(url string, inputType events.EventType) ([]*events.ValidationInfo, error) {
+	vis := []*events.ValidationInfo{}
+	for _, name := range events.InvalidEventNames(inputType) {
+		vi, err := v.ValidateInvalidEvent(url, name, inputType)
+		if err != nil {
+			return nil, err
+		}
+		vis = append(vis, vi)
+	}
+	return vis, nil
+}
+
+// printResults logs validation results and returns an error if any of them failed.
+func printResults(vis []*events.ValidationInfo, err error) error {
+	if err != nil {
+		return err
+	}
+	logStr, err := events.PrintValidationInfos(vis)
+	log.Println(logStr)
+	return err
+}
+
+// Validate runs all validations that apply to the function server listening at url.
+func (v *Validator) Validate(url string) error {
+	if v.validateConcurrency {
+		v.logs.setStep("concurrency")
+		return ValidateConcurrency(url, v.declarativeSignature)
+	}
+	switch v.declarativeSignature {
+	case "http":
+		// Validate HTTP signature, if provided
+		log.Printf("HTTP validation started...")
+		if err := v.ValidateHTTP(url); err != nil {
+			return err
+		}
+		log.Printf("HTTP validation passed!")
+		return nil
+	case "typed":
+		// Validate a typed declarartive function signature
+		log.Printf("Typed validation started...")
+		if err := v.ValidateTyped(url); err != nil {
+			return err
+		}
+		log.Printf("Typed validation passed!")
+		return nil
+	case "cloudevent", "legacyevent":
+		signature := "CloudEvent"
+		if v.declarativeSignature == "legacyevent" {
+			signature = "Legacy event"
+		}
+		for _, c := range v.EventConversions() {
+			log.Printf("%s validation with %s requests...", signature, c.Input)
+			if err := printResults(v.ValidateEvents(url, c)); err != nil {
+				return err
+			}
+		}
+		if v.fuzzCount > 0 {
+			for _, c := range v.EventConversions() {
+				log.Printf("%s validation with generated %s requests...", signature, c.Input)
+				if err := printResults(v.ValidateFuzz(url, c)); err != nil {
+					return err
+				}
+			}
+		}
+		if v.validateInvalid && len(v.InvalidEventTypes()) == 0 {
+			log.Printf("Skipping %s validation with invalid requests: legacy event functions are only sent invalid CloudEvents, which requires mapping validation.", signature)
+		}
+		for _, t := range v.InvalidEventTypes() {
+			log.Printf("%s validation with invalid %s requests...", signature, t)
+			if err := printResults(v.ValidateInvalidEvents(url, t)); err != nil {
+				return err
+			}
+		}
+		log.Printf("%s validation passed!", signature)
+		return nil
+	}
+	return fmt.Errorf("expected --declarative-type to be one of 'http', 'cloudevent', 'legacyevent', or 'typed' got %q", v.declarativeSignature)
+}
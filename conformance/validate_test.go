@@ -0,0 +1,96 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewValidatorSignature(t *testing.T) {
+	testCases := []struct {
+		name            string
+		params          ValidatorParams
+		wantEnvs        []string
+		wantDeclarative string
+	}{
+		{
+			name:            "http",
+			params:          ValidatorParams{FunctionSignature: "http", Envs: []string{"A=1"}},
+			wantEnvs:        []string{"FUNCTION_SIGNATURE_TYPE=http", "A=1"},
+			wantDeclarative: "http",
+		},
+		{
+			name:            "legacy event",
+			params:          ValidatorParams{FunctionSignature: "legacyevent"},
+			wantEnvs:        []string{"FUNCTION_SIGNATURE_TYPE=event"},
+			wantDeclarative: "legacyevent",
+		},
+		{
+			name:            "typed",
+			params:          ValidatorParams{FunctionSignature: "http", DeclarativeSignature: "typed"},
+			wantEnvs:        []string{"FUNCTION_SIGNATURE_TYPE=http"},
+			wantDeclarative: "typed",
+		},
+		{
+			name:     "no signature",
+			params:   ValidatorParams{Envs: []string{"A=1"}},
+			wantEnvs: []string{"A=1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(tc.params)
+			l, ok := v.FunctionServer().(*localFunctionServer)
+			if !ok {
+				t.Fatalf("NewValidator() server = %T, want *localFunctionServer", v.FunctionServer())
+			}
+			if !reflect.DeepEqual(l.envs, tc.wantEnvs) {
+				t.Errorf("NewValidator() server envs = %v, want %v", l.envs, tc.wantEnvs)
+			}
+			if got := v.DeclarativeSignature(); got != tc.wantDeclarative {
+				t.Errorf("DeclarativeSignature() = %q, want %q", got, tc.wantDeclarative)
+			}
+		})
+	}
+}
+
+func TestNewValidatorStartDelay(t *testing.T) {
+	testCases := []struct {
+		name       string
+		startDelay time.Duration
+		want       time.Duration
+	}{
+		{
+			name: "unset",
+			want: defaultStartDelay,
+		},
+		{
+			name:       "set",
+			startDelay: 5 * time.Second,
+			want:       5 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(ValidatorParams{RunCmd: "true", StartDelay: tc.startDelay})
+			l := v.FunctionServer().(*localFunctionServer)
+			if l.startDelay != tc.want {
+				t.Errorf("NewValidator() server start delay = %v, want %v", l.startDelay, tc.want)
+			}
+		})
+	}
+}
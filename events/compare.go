@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// ComparisonPolicy declares which differences between the expected and the received event data
+// are harmless for a particular event. Paths are JSON pointers (RFC 6901) relative to the event
+// data, e.g. "/value/updateTime"; a "*" segment matches any object key or array index.
+type ComparisonPolicy struct {
+	// ExactNumbers requires numbers to be written identically. By default numbers are compared by
+	// value, so that 5, 5.0 and 5e0 are equivalent.
+	ExactNumbers bool `json:"exactNumbers,omitempty"`
+	// NullEqualsAbsent treats an object property with a null value as equivalent to an absent one.
+	NullEqualsAbsent bool `json:"nullEqualsAbsent,omitempty"`
+	// IgnorePaths are the paths of values that are not compared.
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
+	// TimestampPaths are the paths of RFC 3339 strings that are compared as instants in time, so
+	// that "2020-04-23T07:38:57.230Z" and "2020-04-23T07:38:57.230000Z" are equivalent.
+	TimestampPaths []string `json:"timestampPaths,omitempty"`
+	// UnorderedPaths are the paths of arrays whose elements may be received in any order.
+	UnorderedPaths []string `json:"unorderedPaths,omitempty"`
+	// OptionalExtensions are the CloudEvent extension attributes that frameworks may drop. All
+	// other extension attributes of the expected CloudEvent must be preserved.
+	OptionalExtensions []string `json:"optionalExtensions,omitempty"`
+}
+
+// ComparisonPolicyFor returns the comparison policy declared in the test data for a particular
+// event name, or the default policy if there is none.
+func ComparisonPolicyFor(name string) (*ComparisonPolicy, error) {
+	p, err := Events[name].ComparisonPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("event %q: %v", name, err)
+	}
+	return p, nil
+}
+
+// ComparisonPolicy returns the comparison policy declared for the event, or the default policy if
+// there is none.
+func (e Event) ComparisonPolicy() (*ComparisonPolicy, error) {
+	p := &ComparisonPolicy{}
+	if e.Comparison == nil {
+		return p, nil
+	}
+	if err := json.Unmarshal(e.Comparison, p); err != nil {
+		return nil, fmt.Errorf("unmarshalling comparison policy: %v", err)
+	}
+	return p, nil
+}
+
+// Equal reports whether the received value is equivalent to the expected value under the policy.
+// Both values are expected to be decoded with decodeJSON.
+func (p *ComparisonPolicy) Equal(got, want interface{}) bool {
+	return len(p.Diff(got, want)) == 0
+}
+
+// Diff returns the differences between the received and the expected value that are not
+// harmless under the policy, ordered by path. Both values are expected to be decoded with
+// decodeJSON.
+func (p *ComparisonPolicy) Diff(got, want interface{}) []Diff {
+	return p.diff("", got, want, nil)
+}
+
+func (p *ComparisonPolicy) diff(path string, got, want interface{}, diffs []Diff) []Diff {
+	if matchesAnyPath(p.IgnorePaths, path) {
+		return diffs
+	}
+	if jsonTypeName(got) != jsonTypeName(want) {
+		return append(diffs, Diff{Path: path, Kind: DiffType, Got: got, Want: want})
+	}
+
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g := got.(map[string]interface{})
+		for _, k := range unionKeys(g, w) {
+			childPath := path + "/" + escapePathSegment(k)
+			if matchesAnyPath(p.IgnorePaths, childPath) {
+				continue
+			}
+			gv, gotOK := g[k]
+			wv, wantOK := w[k]
+			if gotOK != wantOK && p.NullEqualsAbsent && gv == nil && wv == nil {
+				continue
+			}
+			switch {
+			case !gotOK:
+				diffs = append(diffs, Diff{Path: childPath, Kind: DiffMissing, Want: wv})
+			case !wantOK:
+				diffs = append(diffs, Diff{Path: childPath, Kind: DiffExtra, Got: gv})
+			default:
+				diffs = p.diff(childPath, gv, wv, diffs)
+			}
+		}
+		return diffs
+	case []interface{}:
+		g := got.([]interface{})
+		if matchesAnyPath(p.UnorderedPaths, path) {
+			if len(g) != len(w) || !p.equalUnordered(path, g, w) {
+				diffs = append(diffs, Diff{Path: path, Kind: DiffChanged, Got: got, Want: want})
+			}
+			return diffs
+		}
+		for i := 0; i < len(w) || i < len(g); i++ {
+			childPath := path + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(g):
+				diffs = append(diffs, Diff{Path: childPath, Kind: DiffMissing, Want: w[i]})
+			case i >= len(w):
+				diffs = append(diffs, Diff{Path: childPath, Kind: DiffExtra, Got: g[i]})
+			default:
+				diffs = p.diff(childPath, g[i], w[i], diffs)
+			}
+		}
+		return diffs
+	case json.Number:
+		g := got.(json.Number)
+		if (p.ExactNumbers && g != w) || (!p.ExactNumbers && !numbersEqual(g, w)) {
+			diffs = append(diffs, Diff{Path: path, Kind: DiffChanged, Got: got, Want: want})
+		}
+		return diffs
+	case string:
+		g := got.(string)
+		if g == w || (matchesAnyPath(p.TimestampPaths, path) && timestampsEqual(g, w)) {
+			return diffs
+		}
+		return append(diffs, Diff{Path: path, Kind: DiffChanged, Got: got, Want: want})
+	}
+	if !reflect.DeepEqual(got, want) {
+		diffs = append(diffs, Diff{Path: path, Kind: DiffChanged, Got: got, Want: want})
+	}
+	return diffs
+}
+
+// diffExtensions compares CloudEvent extension attributes by their canonical string
+// representation, as binary mode carries all of them as strings. Extensions that were received but
+// not expected are allowed, as are missing extensions that are declared optional.
+func (p *ComparisonPolicy) diffExtensions(got, want interface{}) []Diff {
+	g, _ := got.(map[string]interface{})
+	w, _ := want.(map[string]interface{})
+	var diffs []Diff
+	for _, k := range unionKeys(nil, w) {
+		wv, _ := types.Format(w[k])
+		gotValue, ok := g[k]
+		if !ok {
+			if !containsString(p.OptionalExtensions, k) {
+				diffs = append(diffs, Diff{Path: "/" + escapePathSegment(k), Kind: DiffMissing, Want: wv})
+			}
+			continue
+		}
+		if gv, _ := types.Format(gotValue); gv != wv {
+			diffs = append(diffs, Diff{Path: "/" + escapePathSegment(k), Kind: DiffChanged, Got: gv, Want: wv})
+		}
+	}
+	return diffs
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// equalUnordered matches every expected element to a distinct received element.
+func (p *ComparisonPolicy) equalUnordered(path string, got, want []interface{}) bool {
+	used := make([]bool, len(got))
+	for i, wv := range want {
+		found := false
+		for j, gv := range got {
+			if !used[j] && len(p.diff(path+"/"+strconv.Itoa(i), gv, wv, nil)) == 0 {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func numbersEqual(got, want json.Number) bool {
+	g, _, gotErr := big.ParseFloat(string(got), 10, 256, big.ToNearestEven)
+	w, _, wantErr := big.ParseFloat(string(want), 10, 256, big.ToNearestEven)
+	if gotErr != nil || wantErr != nil {
+		return got == want
+	}
+	return g.Cmp(w) == 0
+}
+
+func timestampsEqual(got, want string) bool {
+	g, err := time.Parse(time.RFC3339Nano, got)
+	if err != nil {
+		return false
+	}
+	w, err := time.Parse(time.RFC3339Nano, want)
+	if err != nil {
+		return false
+	}
+	return g.Equal(w)
+}
+
+// matchesAnyPath reports whether a JSON pointer matches any of the patterns.
+func matchesAnyPath(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matchesPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i := range patternSegments {
+		if patternSegments[i] != "*" && patternSegments[i] != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// escapePathSegment escapes an object key for use in a JSON pointer.
+func escapePathSegment(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := []string{}
+	for k := range b {
+		keys = append(keys, k)
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeJSON unmarshals JSON, keeping numbers as json.Number so that they can be compared
+// without losing precision.
+func decodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"testing"
+)
+
+func TestComparisonPolicyEqual(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy ComparisonPolicy
+		got    string
+		want   string
+		equal  bool
+	}{
+		{
+			name:  "identical",
+			got:   `{"a": [1, "b", null]}`,
+			want:  `{"a": [1, "b", null]}`,
+			equal: true,
+		},
+		{
+			name:  "integer and float",
+			got:   `{"a": 5.0}`,
+			want:  `{"a": 5}`,
+			equal: true,
+		},
+		{
+			name:   "integer and float with exact numbers",
+			policy: ComparisonPolicy{ExactNumbers: true},
+			got:    `{"a": 5.0}`,
+			want:   `{"a": 5}`,
+		},
+		{
+			name:  "large integers",
+			got:   `{"a": 1587627537231057001}`,
+			want:  `{"a": 1587627537231057000}`,
+			equal: false,
+		},
+		{
+			name:   "timestamp precision",
+			policy: ComparisonPolicy{TimestampPaths: []string{"/t"}},
+			got:    `{"t": "2020-04-23T07:38:57.230000Z"}`,
+			want:   `{"t": "2020-04-23T07:38:57.230Z"}`,
+			equal:  true,
+		},
+		{
+			name:  "timestamp precision outside timestamp paths",
+			got:   `{"t": "2020-04-23T07:38:57.230000Z"}`,
+			want:  `{"t": "2020-04-23T07:38:57.230Z"}`,
+			equal: false,
+		},
+		{
+			name:   "timestamp wildcard",
+			policy: ComparisonPolicy{TimestampPaths: []string{"/*/updateTime"}},
+			got:    `{"value": {"updateTime": "2020-04-23T12:00:27.247187000Z"}}`,
+			want:   `{"value": {"updateTime": "2020-04-23T12:00:27.247187Z"}}`,
+			equal:  true,
+		},
+		{
+			name:   "different timestamps",
+			policy: ComparisonPolicy{TimestampPaths: []string{"/t"}},
+			got:    `{"t": "2020-04-23T07:38:57.231Z"}`,
+			want:   `{"t": "2020-04-23T07:38:57.230Z"}`,
+		},
+		{
+			name:  "null and absent",
+			got:   `{}`,
+			want:  `{"a": null}`,
+			equal: false,
+		},
+		{
+			name:   "null and absent with null equals absent",
+			policy: ComparisonPolicy{NullEqualsAbsent: true},
+			got:    `{}`,
+			want:   `{"a": null}`,
+			equal:  true,
+		},
+		{
+			name:   "ignored path",
+			policy: ComparisonPolicy{IgnorePaths: []string{"/a/b"}},
+			got:    `{"a": {"b": 1, "c": 2}}`,
+			want:   `{"a": {"c": 2}}`,
+			equal:  true,
+		},
+		{
+			name:   "escaped ignored path",
+			policy: ComparisonPolicy{IgnorePaths: []string{"/a~1b"}},
+			got:    `{"a/b": 1}`,
+			want:   `{"a/b": 2}`,
+			equal:  true,
+		},
+		{
+			name:  "unordered array without policy",
+			got:   `{"a": [2, 1]}`,
+			want:  `{"a": [1, 2]}`,
+			equal: false,
+		},
+		{
+			name:   "unordered array",
+			policy: ComparisonPolicy{UnorderedPaths: []string{"/a"}},
+			got:    `{"a": [2, 1]}`,
+			want:   `{"a": [1, 2]}`,
+			equal:  true,
+		},
+		{
+			name:   "unordered array with duplicates",
+			policy: ComparisonPolicy{UnorderedPaths: []string{"/a"}},
+			got:    `{"a": [1, 1]}`,
+			want:   `{"a": [1, 2]}`,
+		},
+		{
+			name:  "type mismatch",
+			got:   `{"a": "5"}`,
+			want:  `{"a": 5}`,
+			equal: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got, want interface{}
+			if err := decodeJSON([]byte(tc.got), &got); err != nil {
+				t.Fatalf("decoding got: %v", err)
+			}
+			if err := decodeJSON([]byte(tc.want), &want); err != nil {
+				t.Fatalf("decoding want: %v", err)
+			}
+			if equal := tc.policy.Equal(got, want); equal != tc.equal {
+				t.Errorf("Equal(%s, %s) = %v, want %v", tc.got, tc.want, equal, tc.equal)
+			}
+		})
+	}
+}
+
+func TestComparisonPolicyFor(t *testing.T) {
+	for name := range Events {
+		if _, err := ComparisonPolicyFor(name); err != nil {
+			t.Errorf("ComparisonPolicyFor(%q): %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DiffKind is the kind of a difference between a received and an expected value.
+type DiffKind string
+
+const (
+	// DiffChanged means the received value differs from the expected value.
+	DiffChanged DiffKind = "changed"
+	// DiffMissing means an expected object key or array element was not received.
+	DiffMissing DiffKind = "missing"
+	// DiffExtra means an object key or array element was received but not expected.
+	DiffExtra DiffKind = "extra"
+	// DiffType means the received value has a different JSON type than the expected value.
+	DiffType DiffKind = "type"
+)
+
+// Diff is a single difference between a received and an expected value.
+type Diff struct {
+	// Path is the JSON pointer of the value, relative to the compared field.
+	Path string      `json:"path"`
+	Kind DiffKind    `json:"kind"`
+	Got  interface{} `json:"got,omitempty"`
+	Want interface{} `json:"want,omitempty"`
+}
+
+func (d Diff) String() string {
+	path := d.Path
+	if path == "" {
+		path = "/"
+	}
+	switch d.Kind {
+	case DiffMissing:
+		return fmt.Sprintf("%s: missing, want %s", path, compactJSON(d.Want))
+	case DiffExtra:
+		return fmt.Sprintf("%s: unexpected, got %s", path, compactJSON(d.Got))
+	case DiffType:
+		return fmt.Sprintf("%s: got %s %s, want %s %s", path, jsonTypeName(d.Got), compactJSON(d.Got), jsonTypeName(d.Want), compactJSON(d.Want))
+	}
+	return fmt.Sprintf("%s: got %s, want %s", path, compactJSON(d.Got), compactJSON(d.Want))
+}
+
+// DiffError is a validation error for a field of an event whose received value does not match
+// the expected value.
+type DiffError struct {
+	Event string      `json:"event"`
+	Field string      `json:"field"`
+	Diffs []Diff      `json:"diffs"`
+	Got   interface{} `json:"-"`
+	Want  interface{} `json:"-"`
+}
+
+// Error lists the paths of each difference, followed by a unified diff of the indented JSON
+// representations if the field is an object or an array.
+func (e *DiffError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unexpected %q in event %q:", e.Field, e.Event)
+	for _, d := range e.Diffs {
+		fmt.Fprintf(&sb, "\n\t\t\t%s", d)
+	}
+	if isContainer(e.Got) || isContainer(e.Want) {
+		for _, line := range strings.Split(unifiedDiff(indentJSON(e.Want), indentJSON(e.Got)), "\n") {
+			if line != "" {
+				fmt.Fprintf(&sb, "\n\t\t\t%s", line)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// jsonTypeName returns the JSON type of a value decoded with decodeJSON.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func indentJSON(v interface{}) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// diffContext is the number of unchanged lines shown around each change in a unified diff.
+const diffContext = 3
+
+// unifiedDiff returns a line-based unified diff from want to got.
+func unifiedDiff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+		// aLine and bLine are the 1-based line numbers before and after the line.
+		aLine, bLine int
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- want\n+++ got\n")
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+		// Extend the hunk while changes are within twice the context of each other.
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		end := start
+		for k := start; k < len(lines) && k <= end+2*diffContext; k++ {
+			if lines[k].op != ' ' {
+				end = k
+			}
+		}
+		to := end + diffContext + 1
+		if to > len(lines) {
+			to = len(lines)
+		}
+
+		var aCount, bCount int
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				aCount++
+			}
+			if l.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", lines[from].aLine, aCount, lines[from].bLine, bCount)
+		for _, l := range lines[from:to] {
+			fmt.Fprintf(&sb, "%c%s\n", l.op, l.text)
+		}
+		start = to
+	}
+	return sb.String()
+}
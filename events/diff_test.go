@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComparisonPolicyDiff(t *testing.T) {
+	var got, want interface{}
+	if err := decodeJSON([]byte(`{"a": {"b": 1, "extra": true}, "c": [1, 2, 3], "d": "5", "e": "x"}`), &got); err != nil {
+		t.Fatalf("decoding got: %v", err)
+	}
+	if err := decodeJSON([]byte(`{"a": {"b": 2, "missing": null}, "c": [1, 2], "d": 5, "e": "x"}`), &want); err != nil {
+		t.Fatalf("decoding want: %v", err)
+	}
+
+	p := &ComparisonPolicy{}
+	var gotPaths []string
+	for _, d := range p.Diff(got, want) {
+		gotPaths = append(gotPaths, fmt.Sprintf("%s %s", d.Kind, d.Path))
+	}
+	wantPaths := []string{
+		"changed /a/b",
+		"extra /a/extra",
+		"missing /a/missing",
+		"extra /c/2",
+		"type /d",
+	}
+	if diff := cmp.Diff(wantPaths, gotPaths); diff != "" {
+		t.Errorf("Diff() paths mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	want := "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}"
+	got := "{\n  \"a\": 1,\n  \"b\": 4,\n  \"c\": 3\n}"
+
+	wantDiff := `--- want
++++ got
+@@ -1,5 +1,5 @@
+ {
+   "a": 1,
+-  "b": 2,
++  "b": 4,
+   "c": 3
+ }
+`
+	if diff := cmp.Diff(wantDiff, unifiedDiff(want, got)); diff != "" {
+		t.Errorf("unifiedDiff() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffError(t *testing.T) {
+	testName := "firebase-auth"
+	data := InputData(testName, CloudEvent)
+	changed := strings.Replace(string(data), `"uid": "UUpby3s4spZre6kHsgVSPetzQ8l2"`, `"uid": "other"`, 1)
+
+	vi := ValidateEvent(testName, CloudEvent, CloudEvent, []byte(changed))
+	if len(vi.Errs) != 1 {
+		t.Fatalf("ValidateEvent() got %d errors, want 1: %v", len(vi.Errs), vi.Errs)
+	}
+	de, ok := vi.Errs[0].(*DiffError)
+	if !ok {
+		t.Fatalf("ValidateEvent() error is %T, want *DiffError", vi.Errs[0])
+	}
+	wantDiffs := []Diff{{Path: "/uid", Kind: DiffChanged, Got: "other", Want: "UUpby3s4spZre6kHsgVSPetzQ8l2"}}
+	if diff := cmp.Diff(wantDiffs, de.Diffs); diff != "" {
+		t.Errorf("DiffError.Diffs mismatch (-want +got):\n%s", diff)
+	}
+	for _, want := range []string{`/uid: got "other", want "UUpby3s4spZre6kHsgVSPetzQ8l2"`, `-  "uid": "UUpby3s4spZre6kHsgVSPetzQ8l2"`, `+  "uid": "other"`} {
+		if !strings.Contains(de.Error(), want) {
+			t.Errorf("DiffError.Error() = %s, want it to contain %q", de.Error(), want)
+		}
+	}
+
+	report, err := json.Marshal(vi)
+	if err != nil {
+		t.Fatalf("marshalling ValidationInfo: %v", err)
+	}
+	wantReport := `{"name":"firebase-auth","status":"failed","errors":[{"event":"firebase-auth","field":"data","diffs":[{"path":"/uid","kind":"changed","got":"other","want":"UUpby3s4spZre6kHsgVSPetzQ8l2"}]}],"metadata":{"description":"A Firebase Auth user creation event, whose uid becomes the CloudEvent subject and whose metadata timestamps are renamed.","spec":"docs/mapping.md#firebase-auth-events"}}`
+	if string(report) != wantReport {
+		t.Errorf("json.Marshal(ValidationInfo) = %s, want %s", report, wantReport)
+	}
+}
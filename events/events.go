@@ -12,12 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:generate go run generate/events_generate.go
-
 // Package events contains the validation logic for different types of events.
 package events
 
 import (
+	"embed"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -45,6 +44,65 @@ func (e EventType) String() string {
 	return ""
 }
 
+// EventData contains the legacy event and CloudEvent representations of an event.
+type EventData struct {
+	LegacyEvent []byte
+	CloudEvent  []byte
+}
+
+// Event is a test case consisting of event inputs and the expected outputs.
+type Event struct {
+	Input           EventData
+	Output          EventData
+	ConvertedOutput EventData
+	// Comparison is the JSON-encoded ComparisonPolicy for the event, if any.
+	Comparison []byte
+	Metadata   Metadata
+}
+
+// Metadata describes why a test case exists.
+type Metadata struct {
+	Description string `json:"description,omitempty"`
+	// Spec references the section of a specification that the test case covers, e.g.
+	// "docs/mapping.md#cloud-storage-events".
+	Spec string `json:"spec,omitempty"`
+	// Optional test cases are reported, but their failures do not fail validation.
+	Optional bool `json:"optional,omitempty"`
+	// KnownDifferences lists the Functions Frameworks known not to pass the test case.
+	KnownDifferences []string `json:"knownDifferences,omitempty"`
+	// ExpectedStatus is the class of the status an invalid event must be rejected with: "4xx",
+	// "5xx", or "error" for either. It is only set for invalid events, which may be rejected with
+	// either if it is not set.
+	ExpectedStatus string `json:"expectedStatus,omitempty"`
+}
+
+// InvalidEvent is a test case consisting of event inputs that Functions Frameworks must reject.
+type InvalidEvent struct {
+	Input    EventData
+	Metadata Metadata
+}
+
+// corpus contains the test data files described in data/README.md.
+//
+//go:embed data
+var corpus embed.FS
+
+var (
+	// Events contains the test cases, keyed by name.
+	Events map[string]Event
+	// InvalidEvents contains events that Functions Frameworks must reject, keyed by name.
+	InvalidEvents map[string]InvalidEvent
+)
+
+func init() {
+	var err error
+	Events, InvalidEvents, err = readEvents(corpus, "data")
+	if err != nil {
+		// The embedded test data is checked by the tests of this package.
+		panic(fmt.Sprintf("reading embedded events: %v", err))
+	}
+}
+
 // EventNames returns a list of event names to use as inputs for a particular event type.
 func EventNames(t EventType) ([]string, error) {
 	eventNames := []string{}
@@ -69,28 +127,64 @@ func EventNames(t EventType) ([]string, error) {
 
 // InputData returns the contents of the input event for a particular event name and type.
 func InputData(name string, t EventType) []byte {
+	return Events[name].InputData(t)
+}
+
+// OutputData returns the contents of the output event for a particular event name and type.
+func OutputData(name string, t EventType, isConversion bool) []byte {
+	return Events[name].OutputData(t, isConversion)
+}
+
+// InputData returns the contents of the input event of a particular type.
+func (e Event) InputData(t EventType) []byte {
 	switch t {
 	case LegacyEvent:
-		return Events[name].Input.LegacyEvent
+		return e.Input.LegacyEvent
 	case CloudEvent:
-		return Events[name].Input.CloudEvent
+		return e.Input.CloudEvent
 	}
 	return nil
 }
 
-// OutputData returns the contents of the output event for a particular event name and type.
-func OutputData(name string, t EventType, isConversion bool) []byte {
+// OutputData returns the contents of the output event of a particular type.
+func (e Event) OutputData(t EventType, isConversion bool) []byte {
 	switch t {
 	case LegacyEvent:
-		if isConversion && Events[name].ConvertedOutput.LegacyEvent != nil {
-			return Events[name].ConvertedOutput.LegacyEvent
+		if isConversion && e.ConvertedOutput.LegacyEvent != nil {
+			return e.ConvertedOutput.LegacyEvent
 		}
-		return Events[name].Output.LegacyEvent
+		return e.Output.LegacyEvent
 	case CloudEvent:
-		if isConversion && Events[name].ConvertedOutput.CloudEvent != nil {
-			return Events[name].ConvertedOutput.CloudEvent
+		if isConversion && e.ConvertedOutput.CloudEvent != nil {
+			return e.ConvertedOutput.CloudEvent
+		}
+		return e.Output.CloudEvent
+	}
+	return nil
+}
+
+// InvalidEventNames returns a list of invalid event names to use as inputs for a particular event type.
+func InvalidEventNames(t EventType) []string {
+	eventNames := []string{}
+	for name := range InvalidEvents {
+		if InvalidInputData(name, t) != nil {
+			eventNames = append(eventNames, name)
 		}
-		return Events[name].Output.CloudEvent
+	}
+
+	// Sort the event names for deterministic output.
+	sort.Strings(eventNames)
+
+	return eventNames
+}
+
+// InvalidInputData returns the contents of the invalid input event for a particular event name and type.
+func InvalidInputData(name string, t EventType) []byte {
+	switch t {
+	case LegacyEvent:
+		return InvalidEvents[name].Input.LegacyEvent
+	case CloudEvent:
+		return InvalidEvents[name].Input.CloudEvent
 	}
 	return nil
 }
@@ -0,0 +1,578 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FuzzCase is a randomly generated event from which all representations of the event, and the
+// expected results of converting between them, are derived following docs/mapping.md.
+type FuzzCase struct {
+	Service   string
+	ID        string
+	Timestamp time.Time
+	// Resource contains the service-specific parts of the resource name, e.g. the bucket and
+	// the object name for Cloud Storage.
+	Resource []string
+	// Data is the service-specific, free-form part of the event data.
+	Data interface{}
+}
+
+type fuzzService struct {
+	// generate returns random resource name parts and data.
+	generate func(r *rand.Rand) ([]string, interface{})
+	// simplestResource are the resource name parts that shrinking converges to.
+	simplestResource []string
+	// spec is the section of docs/mapping.md that describes the conversion of the events.
+	spec string
+	// event builds all representations of the event.
+	event func(c FuzzCase) Event
+}
+
+var fuzzServices = map[string]fuzzService{
+	"pubsub": {
+		generate: func(r *rand.Rand) ([]string, interface{}) {
+			attributes := map[string]interface{}{}
+			for i := r.Intn(5); i > 0; i-- {
+				attributes[randomKey(r)] = randomString(r)
+			}
+			payload := make([]byte, r.Intn(64))
+			r.Read(payload)
+			return []string{randomName(r), randomName(r)}, map[string]interface{}{
+				"attributes": attributes,
+				"data":       base64.StdEncoding.EncodeToString(payload),
+			}
+		},
+		simplestResource: []string{"p", "t"},
+		spec:             "docs/mapping.md#cloud-pubsub-events",
+		event: func(c FuzzCase) Event {
+			name := fmt.Sprintf("projects/%s/topics/%s", c.Resource[0], c.Resource[1])
+			message := copyObject(c.Data)
+			message["@type"] = "type.googleapis.com/google.pubsub.v1.PubsubMessage"
+			legacy := map[string]interface{}{
+				"context": map[string]interface{}{
+					"eventId":   c.ID,
+					"timestamp": c.timestamp(),
+					"eventType": "google.pubsub.topic.publish",
+					"resource": map[string]interface{}{
+						"service": "pubsub.googleapis.com",
+						"name":    name,
+						"type":    "type.googleapis.com/google.pubsub.v1.PubsubMessage",
+					},
+				},
+				"data": message,
+			}
+			wrapped := copyObject(message)
+			wrapped["messageId"] = c.ID
+			wrapped["publishTime"] = c.timestamp()
+			ce := c.cloudEvent("google.cloud.pubsub.topic.v1.messagePublished", "//pubsub.googleapis.com/"+name, "", map[string]interface{}{
+				"message": wrapped,
+			})
+			return fuzzEvent(legacy, legacy, ce)
+		},
+	},
+	"storage": {
+		generate: func(r *rand.Rand) ([]string, interface{}) {
+			// Object names may contain slashes and any unicode character.
+			segments := []string{}
+			for i := r.Intn(4); i >= 0; i-- {
+				segments = append(segments, randomString(r)+"x")
+			}
+			metadata := map[string]interface{}{}
+			for i := r.Intn(5); i > 0; i-- {
+				metadata[randomKey(r)] = randomString(r)
+			}
+			return []string{randomName(r), strings.Join(segments, "/")}, metadata
+		},
+		simplestResource: []string{"b", "o"},
+		spec:             "docs/mapping.md#cloud-storage-events",
+		event: func(c FuzzCase) Event {
+			bucket, object := c.Resource[0], c.Resource[1]
+			data := map[string]interface{}{
+				"bucket":   bucket,
+				"name":     object,
+				"kind":     "storage#object",
+				"metadata": c.Data,
+			}
+			legacy := map[string]interface{}{
+				"context": map[string]interface{}{
+					"eventId":   c.ID,
+					"timestamp": c.timestamp(),
+					"eventType": "google.storage.object.finalize",
+					"resource": map[string]interface{}{
+						"service": "storage.googleapis.com",
+						"name":    fmt.Sprintf("projects/_/buckets/%s/objects/%s", bucket, object),
+						"type":    "storage#object",
+					},
+				},
+				"data": data,
+			}
+			ce := c.cloudEvent("google.cloud.storage.object.v1.finalized", "//storage.googleapis.com/projects/_/buckets/"+bucket, "objects/"+object, data)
+			return fuzzEvent(legacy, legacy, ce)
+		},
+	},
+	"firestore": {
+		generate: func(r *rand.Rand) ([]string, interface{}) {
+			path := []string{}
+			for i := r.Intn(3); i >= 0; i-- {
+				path = append(path, randomName(r), randomName(r))
+			}
+			database := "(default)"
+			if r.Intn(2) == 0 {
+				database = randomName(r)
+			}
+			fields := map[string]interface{}{}
+			for i := r.Intn(6); i > 0; i-- {
+				fields[randomKey(r)] = randomFirestoreValue(r, 4)
+			}
+			return []string{randomName(r), database, strings.Join(path, "/")}, fields
+		},
+		simplestResource: []string{"p", "(default)", "c/d"},
+		spec:             "docs/mapping.md#firestore-document-events",
+		event: func(c FuzzCase) Event {
+			project, database, path := c.Resource[0], c.Resource[1], c.Resource[2]
+			resource := fmt.Sprintf("projects/%s/databases/%s/documents/%s", project, database, path)
+			data := map[string]interface{}{
+				"oldValue":   map[string]interface{}{},
+				"updateMask": map[string]interface{}{},
+				"value": map[string]interface{}{
+					"createTime": "2020-04-23T14:25:05.349632Z",
+					"fields":     c.Data,
+					"name":       resource,
+					"updateTime": "2020-04-23T14:25:05.349632Z",
+				},
+			}
+			const eventType = "providers/cloud.firestore/eventTypes/document.write"
+			legacyInput := map[string]interface{}{
+				"data":      data,
+				"eventId":   c.ID,
+				"eventType": eventType,
+				"resource":  resource,
+				"timestamp": c.timestamp(),
+			}
+			legacyOutput := map[string]interface{}{
+				"data": data,
+				"context": map[string]interface{}{
+					"eventId":   c.ID,
+					"eventType": eventType,
+					"resource":  resource,
+					"timestamp": c.timestamp(),
+				},
+			}
+			ce := c.cloudEvent("google.cloud.firestore.document.v1.written", fmt.Sprintf("//firestore.googleapis.com/projects/%s/databases/%s", project, database), "documents/"+path, data)
+			e := fuzzEvent(legacyInput, legacyOutput, ce)
+			e.Comparison = mustIndentJSON(ComparisonPolicy{
+				TimestampPaths: []string{"/value/createTime", "/value/updateTime"},
+			})
+			return e
+		},
+	},
+	"rtdb": {
+		generate: func(r *rand.Rand) ([]string, interface{}) {
+			domains := []string{"firebaseio.com", "europe-west1.firebasedatabase.app", "asia-southeast1.firebasedatabase.app"}
+			path := []string{}
+			for i := r.Intn(4); i >= 0; i-- {
+				path = append(path, randomName(r))
+			}
+			return []string{randomName(r), domains[r.Intn(len(domains))], strings.Join(path, "/")}, map[string]interface{}{
+				"data":  randomValue(r, 6),
+				"delta": randomValue(r, 6),
+			}
+		},
+		simplestResource: []string{"i", "firebaseio.com", "r"},
+		spec:             "docs/mapping.md#firebase-rtdb-events",
+		event: func(c FuzzCase) Event {
+			instance, domain, path := c.Resource[0], c.Resource[1], c.Resource[2]
+			location := "us-central1"
+			if domain != "firebaseio.com" {
+				location = strings.SplitN(domain, ".", 2)[0]
+			}
+			resource := fmt.Sprintf("projects/_/instances/%s/refs/%s", instance, path)
+			const eventType = "providers/google.firebase.database/eventTypes/ref.write"
+			legacyInput := map[string]interface{}{
+				"eventType": eventType,
+				"domain":    domain,
+				"data":      c.Data,
+				"resource":  resource,
+				"timestamp": c.timestamp(),
+				"eventId":   c.ID,
+			}
+			legacyOutput := map[string]interface{}{
+				"data": c.Data,
+				"context": map[string]interface{}{
+					"resource":  resource,
+					"timestamp": c.timestamp(),
+					"eventId":   c.ID,
+					"eventType": eventType,
+				},
+			}
+			ce := c.cloudEvent("google.firebase.database.ref.v1.written", fmt.Sprintf("//firebasedatabase.googleapis.com/projects/_/locations/%s/instances/%s", location, instance), "refs/"+path, c.Data)
+			return fuzzEvent(legacyInput, legacyOutput, ce)
+		},
+	},
+}
+
+// FuzzServices returns the services for which events can be generated, sorted by name.
+func FuzzServices() []string {
+	services := []string{}
+	for s := range fuzzServices {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// GenerateFuzzCase returns a random but valid event for a service.
+func GenerateFuzzCase(r *rand.Rand, service string) (FuzzCase, error) {
+	s, ok := fuzzServices[service]
+	if !ok {
+		return FuzzCase{}, fmt.Errorf("unknown fuzz service %q, must be one of %v", service, FuzzServices())
+	}
+	resource, data := s.generate(r)
+	return FuzzCase{
+		Service:   service,
+		ID:        strconv.FormatUint(r.Uint64(), 16),
+		Timestamp: randomTimestamp(r),
+		Resource:  resource,
+		Data:      data,
+	}, nil
+}
+
+// Event returns all representations of the generated event.
+func (c FuzzCase) Event() Event {
+	s := fuzzServices[c.Service]
+	e := s.event(c)
+	e.Metadata = Metadata{
+		Description: fmt.Sprintf("A randomly generated %s event.", c.Service),
+		Spec:        s.spec,
+	}
+	return e
+}
+
+// Shrink returns simpler variants of the case, simplest first, to find a minimal failing input.
+func (c FuzzCase) Shrink() []FuzzCase {
+	var candidates []FuzzCase
+	if simplest := fuzzTimestamp; !c.Timestamp.Equal(simplest) {
+		s := c
+		s.Timestamp = simplest
+		candidates = append(candidates, s)
+	}
+	if c.ID != fuzzID {
+		s := c
+		s.ID = fuzzID
+		candidates = append(candidates, s)
+	}
+	for i, simplest := range fuzzServices[c.Service].simplestResource {
+		if c.Resource[i] != simplest {
+			s := c
+			s.Resource = append([]string{}, c.Resource...)
+			s.Resource[i] = simplest
+			candidates = append(candidates, s)
+		}
+	}
+	for _, data := range shrinkValue(c.Data) {
+		s := c
+		s.Data = data
+		candidates = append(candidates, s)
+	}
+	return candidates
+}
+
+// WriteEventFiles writes the representations of an event to dir as test data files named after
+// name, in the layout of events/data.
+func WriteEventFiles(dir, name string, e Event) error {
+	files := map[string][]byte{
+		"legacy-input":      e.Input.LegacyEvent,
+		"cloudevent-input":  e.Input.CloudEvent,
+		"legacy-output":     e.Output.LegacyEvent,
+		"cloudevent-output": e.Output.CloudEvent,
+		"comparison":        e.Comparison,
+	}
+	if !reflect.DeepEqual(e.Metadata, Metadata{}) {
+		files["metadata"] = mustIndentJSON(e.Metadata)
+	}
+	for suffix, data := range files {
+		if data == nil {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", name, suffix))
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("writing %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+var (
+	fuzzTimestamp = time.Date(2020, 9, 29, 11, 32, 0, 123000000, time.UTC)
+	fuzzID        = "aaaaaa-1111-bbbb-2222-cccccccccccc"
+)
+
+func (c FuzzCase) timestamp() string {
+	return c.Timestamp.UTC().Format(time.RFC3339Nano)
+}
+
+func (c FuzzCase) cloudEvent(ceType, source, subject string, data interface{}) map[string]interface{} {
+	ce := map[string]interface{}{
+		"specversion":     "1.0",
+		"type":            ceType,
+		"source":          source,
+		"id":              c.ID,
+		"time":            c.timestamp(),
+		"datacontenttype": "application/json",
+		"data":            data,
+	}
+	if subject != "" {
+		ce["subject"] = subject
+	}
+	return ce
+}
+
+func fuzzEvent(legacyInput, legacyOutput, ce map[string]interface{}) Event {
+	return Event{
+		Input: EventData{
+			LegacyEvent: mustIndentJSON(legacyInput),
+			CloudEvent:  mustIndentJSON(ce),
+		},
+		Output: EventData{
+			LegacyEvent: mustIndentJSON(legacyOutput),
+			CloudEvent:  mustIndentJSON(ce),
+		},
+	}
+}
+
+func mustIndentJSON(v interface{}) []byte {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		// Generated values only contain JSON types.
+		panic(fmt.Sprintf("marshalling generated event: %v", err))
+	}
+	return append(b, '\n')
+}
+
+func copyObject(v interface{}) map[string]interface{} {
+	c := map[string]interface{}{}
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, v := range m {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+// maxShrinkElements is the largest array whose elements are individually shrunk.
+const maxShrinkElements = 8
+
+// shrinkValue returns simpler variants of a JSON value: coarse simplifications, such as removing
+// a whole object property, come before finer ones inside it.
+func shrinkValue(v interface{}) []interface{} {
+	var candidates []interface{}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			c := copyObject(t)
+			delete(c, k)
+			candidates = append(candidates, c)
+		}
+		for _, k := range keys {
+			for _, sub := range shrinkValue(t[k]) {
+				c := copyObject(t)
+				c[k] = sub
+				candidates = append(candidates, c)
+			}
+		}
+	case []interface{}:
+		if len(t) > 1 {
+			candidates = append(candidates, append([]interface{}{}, t[:len(t)/2]...))
+			candidates = append(candidates, append([]interface{}{}, t[len(t)/2:]...))
+		}
+		if len(t) > maxShrinkElements {
+			// Halving quickly gets large arrays to a size where elements are shrunk one by one.
+			break
+		}
+		for i := range t {
+			c := append(append([]interface{}{}, t[:i]...), t[i+1:]...)
+			candidates = append(candidates, c)
+		}
+		for i := range t {
+			for _, sub := range shrinkValue(t[i]) {
+				c := append([]interface{}{}, t...)
+				c[i] = sub
+				candidates = append(candidates, c)
+			}
+		}
+	case string:
+		if t != "" {
+			candidates = append(candidates, "")
+		}
+	}
+	return candidates
+}
+
+// boundaryTimestamps are timestamps that commonly expose parsing and formatting issues.
+var boundaryTimestamps = []time.Time{
+	time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2020, 2, 29, 23, 59, 59, 999000000, time.UTC),
+	time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC),
+	time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC),
+}
+
+// randomTimestamp returns a boundary timestamp or a random one, with at most millisecond
+// precision so that every language can represent it.
+func randomTimestamp(r *rand.Rand) time.Time {
+	if r.Intn(2) == 0 {
+		return boundaryTimestamps[r.Intn(len(boundaryTimestamps))]
+	}
+	return time.Unix(r.Int63n(4102444800), int64(r.Intn(1000))*int64(time.Millisecond)).UTC()
+}
+
+const nameChars = "abcdefghijklmnopqrstuvwxyz0123456789-"
+
+// randomName returns a random name that is valid in resource names of all services.
+func randomName(r *rand.Rand) string {
+	b := []byte{byte('a' + r.Intn(26))}
+	for i := r.Intn(20) + 2; i > 0; i-- {
+		b = append(b, nameChars[r.Intn(len(nameChars))])
+	}
+	return string(b)
+}
+
+var stringRunes = []rune("abcXYZ019 _-.:é日本語ключ🙂\"\\\t")
+
+func randomString(r *rand.Rand) string {
+	var sb strings.Builder
+	for i := r.Intn(16); i > 0; i-- {
+		sb.WriteRune(stringRunes[r.Intn(len(stringRunes))])
+	}
+	return sb.String()
+}
+
+// randomKey returns a random non-empty object key, which may contain unicode characters.
+func randomKey(r *rand.Rand) string {
+	return randomName(r)[:1] + strings.NewReplacer(".", "", "/", "").Replace(randomString(r))
+}
+
+// randomValue returns a random JSON value nested at most depth levels deep. Occasionally, it
+// returns a deeply nested chain of objects or a large array.
+func randomValue(r *rand.Rand, depth int) interface{} {
+	n := r.Intn(20)
+	switch {
+	case depth > 0 && n == 0:
+		var v interface{} = randomScalar(r)
+		for i := r.Intn(32) + 16; i > 0; i-- {
+			v = map[string]interface{}{randomKey(r): v}
+		}
+		return v
+	case depth > 0 && n == 1:
+		a := make([]interface{}, r.Intn(500)+100)
+		for i := range a {
+			a[i] = randomScalar(r)
+		}
+		return a
+	case depth > 0 && n < 8:
+		return randomObject(r, depth-1)
+	case depth > 0 && n < 11:
+		a := make([]interface{}, r.Intn(5))
+		for i := range a {
+			a[i] = randomValue(r, depth-1)
+		}
+		return a
+	}
+	return randomScalar(r)
+}
+
+func randomObject(r *rand.Rand, depth int) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i := r.Intn(5); i > 0; i-- {
+		m[randomKey(r)] = randomValue(r, depth)
+	}
+	return m
+}
+
+func randomScalar(r *rand.Rand) interface{} {
+	switch r.Intn(6) {
+	case 0:
+		return nil
+	case 1:
+		return r.Intn(2) == 0
+	case 2:
+		// Integers that are exactly representable as doubles.
+		return json.Number(strconv.FormatInt(r.Int63n(1<<53)-1<<52, 10))
+	case 3:
+		return json.Number(strconv.FormatFloat(float64(r.Int63n(2000000)-1000000)/1000, 'f', -1, 64))
+	}
+	return randomString(r)
+}
+
+// randomFirestoreValue returns a random Firestore value in its JSON representation.
+func randomFirestoreValue(r *rand.Rand, depth int) interface{} {
+	n := r.Intn(11)
+	if depth <= 0 && n >= 9 {
+		n = r.Intn(9)
+	}
+	switch n {
+	case 0:
+		return map[string]interface{}{"nullValue": nil}
+	case 1:
+		return map[string]interface{}{"booleanValue": r.Intn(2) == 0}
+	case 2:
+		return map[string]interface{}{"integerValue": strconv.FormatInt(int64(r.Uint64()), 10)}
+	case 3:
+		return map[string]interface{}{"doubleValue": json.Number(strconv.FormatFloat(float64(r.Int63n(2000000)-1000000)/1000, 'f', -1, 64))}
+	case 4:
+		return map[string]interface{}{"timestampValue": randomTimestamp(r).Format(time.RFC3339Nano)}
+	case 5:
+		b := make([]byte, r.Intn(16))
+		r.Read(b)
+		return map[string]interface{}{"bytesValue": base64.StdEncoding.EncodeToString(b)}
+	case 6:
+		return map[string]interface{}{"referenceValue": fmt.Sprintf("projects/%s/databases/(default)/documents/%s/%s", randomName(r), randomName(r), randomName(r))}
+	case 7:
+		return map[string]interface{}{"geoPointValue": map[string]interface{}{
+			"latitude":  json.Number(strconv.FormatFloat(float64(r.Intn(180000)-90000)/1000, 'f', -1, 64)),
+			"longitude": json.Number(strconv.FormatFloat(float64(r.Intn(360000)-180000)/1000, 'f', -1, 64)),
+		}}
+	case 8:
+		return map[string]interface{}{"stringValue": randomString(r)}
+	case 9:
+		values := make([]interface{}, r.Intn(5))
+		for i := range values {
+			values[i] = randomFirestoreValue(r, depth-1)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	}
+	fields := map[string]interface{}{}
+	for i := r.Intn(4); i > 0; i-- {
+		fields[randomKey(r)] = randomFirestoreValue(r, depth-1)
+	}
+	return map[string]interface{}{"mapValue": map[string]interface{}{"fields": fields}}
+}
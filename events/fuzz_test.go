@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateFuzzCaseDeterministic(t *testing.T) {
+	for _, service := range FuzzServices() {
+		t.Run(service, func(t *testing.T) {
+			a, err := GenerateFuzzCase(rand.New(rand.NewSource(42)), service)
+			if err != nil {
+				t.Fatalf("GenerateFuzzCase(%q): %v", service, err)
+			}
+			b, err := GenerateFuzzCase(rand.New(rand.NewSource(42)), service)
+			if err != nil {
+				t.Fatalf("GenerateFuzzCase(%q): %v", service, err)
+			}
+			if diff := cmp.Diff(a.Event(), b.Event()); diff != "" {
+				t.Errorf("GenerateFuzzCase(%q) with the same seed mismatch (-first +second):\n%s", service, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateFuzzCaseUnknownService(t *testing.T) {
+	if _, err := GenerateFuzzCase(rand.New(rand.NewSource(1)), "unknown"); err == nil {
+		t.Errorf("GenerateFuzzCase(%q) got nil error, want error", "unknown")
+	}
+}
+
+// TestFuzzEventsSelfValidate checks that the expected outputs of generated events, and of their
+// shrunk variants, pass validation.
+func TestFuzzEventsSelfValidate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, service := range FuzzServices() {
+		for i := 0; i < 5; i++ {
+			c, err := GenerateFuzzCase(r, service)
+			if err != nil {
+				t.Fatalf("GenerateFuzzCase(%q): %v", service, err)
+			}
+			cases := append([]FuzzCase{c}, c.Shrink()...)
+			for _, c := range cases {
+				validateFuzzCase(t, c)
+			}
+		}
+	}
+}
+
+func validateFuzzCase(t *testing.T, c FuzzCase) {
+	t.Helper()
+	e := c.Event()
+	for _, it := range []EventType{LegacyEvent, CloudEvent} {
+		for _, ot := range []EventType{LegacyEvent, CloudEvent} {
+			got := e.OutputData(ot, it != ot)
+			if it == CloudEvent && ot == CloudEvent {
+				got = e.InputData(it)
+			}
+			if vi := ValidateEventData(c.Service, e, it, ot, got); vi.Errs != nil {
+				t.Errorf("ValidateEventData(%s, %v -> %v) got errors for %+v: %v", c.Service, it, ot, c, vi.Errs)
+			}
+		}
+	}
+}
+
+func TestFuzzCaseShrink(t *testing.T) {
+	c, err := GenerateFuzzCase(rand.New(rand.NewSource(7)), "rtdb")
+	if err != nil {
+		t.Fatalf("GenerateFuzzCase: %v", err)
+	}
+	// Repeatedly taking the first candidate must terminate at the simplest case.
+	for i := 0; i < 10000; i++ {
+		candidates := c.Shrink()
+		if len(candidates) == 0 {
+			break
+		}
+		c = candidates[0]
+	}
+	if got := c.Shrink(); len(got) != 0 {
+		t.Fatalf("Shrink() did not converge, got %d candidates for %+v", len(got), c)
+	}
+	want := FuzzCase{
+		Service:   "rtdb",
+		ID:        fuzzID,
+		Timestamp: fuzzTimestamp,
+		Resource:  []string{"i", "firebaseio.com", "r"},
+		Data:      map[string]interface{}{},
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Errorf("shrunk case mismatch (-want +got):\n%s", diff)
+	}
+}
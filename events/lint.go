@@ -0,0 +1,317 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// legacyToCloudEventTypes maps legacy event types to CloudEvent types, as described in
+// docs/mapping.md.
+var legacyToCloudEventTypes = map[string]string{
+	"google.pubsub.topic.publish":                              "google.cloud.pubsub.topic.v1.messagePublished",
+	"providers/cloud.pubsub/eventTypes/topic.publish":          "google.cloud.pubsub.topic.v1.messagePublished",
+	"google.storage.object.finalize":                           "google.cloud.storage.object.v1.finalized",
+	"google.storage.object.delete":                             "google.cloud.storage.object.v1.deleted",
+	"google.storage.object.archive":                            "google.cloud.storage.object.v1.archived",
+	"google.storage.object.metadataUpdate":                     "google.cloud.storage.object.v1.metadataUpdated",
+	"providers/cloud.firestore/eventTypes/document.write":      "google.cloud.firestore.document.v1.written",
+	"providers/cloud.firestore/eventTypes/document.create":     "google.cloud.firestore.document.v1.created",
+	"providers/cloud.firestore/eventTypes/document.update":     "google.cloud.firestore.document.v1.updated",
+	"providers/cloud.firestore/eventTypes/document.delete":     "google.cloud.firestore.document.v1.deleted",
+	"providers/firebase.auth/eventTypes/user.create":           "google.firebase.auth.user.v1.created",
+	"providers/firebase.auth/eventTypes/user.delete":           "google.firebase.auth.user.v1.deleted",
+	"providers/firebase.remoteConfig/remoteconfig.update":      "google.firebase.remoteconfig.remoteConfig.v1.updated",
+	"providers/google.firebase.analytics/eventTypes/event.log": "google.firebase.analytics.log.v1.written",
+	"providers/google.firebase.database/eventTypes/ref.create": "google.firebase.database.ref.v1.created",
+	"providers/google.firebase.database/eventTypes/ref.write":  "google.firebase.database.ref.v1.written",
+	"providers/google.firebase.database/eventTypes/ref.update": "google.firebase.database.ref.v1.updated",
+	"providers/google.firebase.database/eventTypes/ref.delete": "google.firebase.database.ref.v1.deleted",
+}
+
+// legacyServicePrefixes maps legacy event type prefixes to the service of events without a
+// context/resource/service value, as described in docs/mapping.md.
+var legacyServicePrefixes = map[string]string{
+	"providers/cloud.firestore/":           "firestore.googleapis.com",
+	"providers/google.firebase.analytics/": "firebaseanalytics.googleapis.com",
+	"providers/firebase.auth/":             "firebaseauth.googleapis.com",
+	"providers/google.firebase.database/":  "firebasedatabase.googleapis.com",
+	"providers/cloud.pubsub/":              "pubsub.googleapis.com",
+	"providers/cloud.storage/":             "storage.googleapis.com",
+}
+
+// LintError is a problem with a test data file.
+type LintError struct {
+	File string
+	Msg  string
+}
+
+func (e LintError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Msg)
+}
+
+// LintEvents checks the test data files in dir, which follow the layout of events/data: that files
+// are named following the naming convention and contain valid JSON, that CloudEvents and metadata
+// are valid, that every test case has a legacy and a CloudEvent input and output, unless it only
+// has a CloudEvent input and output, that invalid test cases have metadata with the expected
+// status, and that the legacy and CloudEvent representations of each test case agree with
+// docs/mapping.md. The returned error is only set if the files could not be
+// read.
+func LintEvents(dir string) ([]LintError, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q: %v", dir, err)
+	}
+
+	var lint []LintError
+	// files maps test case names to the parsed files of the test case, keyed by file name.
+	files := map[string]map[string]interface{}{}
+	var names []string
+	// invalid are the names of the invalid test cases.
+	invalid := map[string]bool{}
+	for _, info := range infos {
+		if info.IsDir() || strings.HasSuffix(info.Name(), ".md") {
+			continue
+		}
+		f, ok := parseDataFileName(info.Name())
+		if !ok {
+			lint = append(lint, LintError{info.Name(), "name does not match <name>-<legacy|cloudevent>-<input|output>[-converted|-invalid].json or <name>-comparison.json"})
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading file %q: %v", info.Name(), err)
+		}
+		var v interface{}
+		if err := decodeJSON(data, &v); err != nil {
+			lint = append(lint, LintError{info.Name(), fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		if _, ok := files[f.name]; !ok {
+			files[f.name] = map[string]interface{}{}
+			names = append(names, f.name)
+		}
+		if f.variant == "invalid" {
+			// Invalid inputs are malformed on purpose.
+			invalid[f.name] = true
+			continue
+		}
+
+		switch f.fileType {
+		case "comparison":
+			if _, err := (Event{Comparison: data}).ComparisonPolicy(); err != nil {
+				lint = append(lint, LintError{info.Name(), err.Error()})
+			}
+		case "metadata":
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			var m Metadata
+			if err := dec.Decode(&m); err != nil {
+				lint = append(lint, LintError{info.Name(), fmt.Sprintf("invalid metadata: %v", err)})
+			} else if m.Description == "" {
+				lint = append(lint, LintError{info.Name(), "missing description"})
+			} else if !validStatusClasses[m.ExpectedStatus] {
+				lint = append(lint, LintError{info.Name(), fmt.Sprintf("got expectedStatus %q, want '4xx', '5xx' or 'error'", m.ExpectedStatus)})
+			}
+		default:
+			if _, ok := v.(map[string]interface{}); !ok {
+				lint = append(lint, LintError{info.Name(), fmt.Sprintf("got JSON %s, want object", jsonTypeName(v))})
+				continue
+			}
+		}
+		if f.eventType == "cloudevent" {
+			if err := validateCloudEventFile(data); err != nil {
+				lint = append(lint, LintError{info.Name(), err.Error()})
+				continue
+			}
+		}
+
+		files[f.name][info.Name()] = v
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		if invalid[name] {
+			lint = append(lint, lintInvalidCase(name, files[name])...)
+			continue
+		}
+		lint = append(lint, lintCase(name, files[name])...)
+	}
+	return lint, nil
+}
+
+func validateCloudEventFile(data []byte) error {
+	ce, err := BuildCloudEvent(data)
+	if err != nil {
+		return err
+	}
+	if err := ce.Validate(); err != nil {
+		return fmt.Errorf("invalid cloud event: %v", err)
+	}
+	return nil
+}
+
+// validStatusClasses are the expected status classes of invalid test cases, or empty.
+var validStatusClasses = map[string]bool{"": true, "4xx": true, "5xx": true, "error": true}
+
+// lintInvalidCase checks that an invalid test case only has input and metadata files, and that its
+// metadata describes the expected status.
+func lintInvalidCase(name string, files map[string]interface{}) []LintError {
+	var lint []LintError
+	file := name + "-metadata.json"
+	var others []string
+	for f := range files {
+		if f != file {
+			others = append(others, f)
+		}
+	}
+	sort.Strings(others)
+	for _, f := range others {
+		lint = append(lint, LintError{f, "invalid test cases only have invalid input and metadata files"})
+	}
+	m, ok := files[file].(map[string]interface{})
+	if !ok {
+		return append(lint, LintError{file, "missing"})
+	}
+	if _, ok := m["expectedStatus"]; !ok {
+		lint = append(lint, LintError{file, "missing expectedStatus"})
+	}
+	return lint
+}
+
+// lintCase checks that the files of a valid test case are complete and consistent.
+func lintCase(name string, files map[string]interface{}) []LintError {
+	var lint []LintError
+	get := func(suffix string) map[string]interface{} {
+		m, _ := files[name+"-"+suffix+".json"].(map[string]interface{})
+		return m
+	}
+	if m := get("metadata"); m != nil {
+		if _, ok := m["expectedStatus"]; ok {
+			lint = append(lint, LintError{name + "-metadata.json", "expectedStatus is only for invalid test cases"})
+		}
+	}
+	// A case of CloudEvent features that legacy events cannot represent, e.g. extension
+	// attributes, has no legacy files.
+	cloudEventOnly := get("legacy-input") == nil && get("legacy-output") == nil &&
+		get("cloudevent-input") != nil && get("cloudevent-output") != nil
+	for _, suffix := range []string{"legacy-input", "legacy-output", "cloudevent-input", "cloudevent-output"} {
+		if get(suffix) == nil && !cloudEventOnly {
+			lint = append(lint, LintError{name + "-" + suffix + ".json", "missing"})
+		}
+	}
+	legacyInput := get("legacy-input")
+	if legacyInput == nil {
+		return lint
+	}
+
+	id, timestamp, eventType, service, resource := legacyContext(legacyInput)
+	project := projectOf(resource)
+	file := name + "-legacy-input.json"
+	ceType, ok := legacyToCloudEventTypes[eventType]
+	if !ok {
+		lint = append(lint, LintError{file, fmt.Sprintf("unknown legacy event type %q", eventType)})
+	}
+
+	// The legacy outputs must describe the same event. Converted outputs may use another legacy
+	// event type that maps to the same CloudEvent type.
+	for _, suffix := range []string{"legacy-output", "legacy-output-converted"} {
+		output := get(suffix)
+		if output == nil {
+			continue
+		}
+		outFile := name + "-" + suffix + ".json"
+		outID, outTimestamp, outType, _, outResource := legacyContext(output)
+		if outID != id {
+			lint = append(lint, LintError{outFile, fmt.Sprintf("got event ID %q, want %q as in %s", outID, id, file)})
+		}
+		if !timestampsEqual(outTimestamp, timestamp) {
+			lint = append(lint, LintError{outFile, fmt.Sprintf("got timestamp %q, want %q as in %s", outTimestamp, timestamp, file)})
+		}
+		if outType != eventType && (!ok || legacyToCloudEventTypes[outType] != ceType) {
+			lint = append(lint, LintError{outFile, fmt.Sprintf("got event type %q, want %q as in %s", outType, eventType, file)})
+		}
+		if outProject := projectOf(outResource); outProject != project {
+			lint = append(lint, LintError{outFile, fmt.Sprintf("got project %q, want %q as in %s", outProject, project, file)})
+		}
+	}
+
+	// The CloudEvent representations must map to the legacy input.
+	for _, suffix := range []string{"cloudevent-input", "cloudevent-output", "cloudevent-output-converted"} {
+		ce := get(suffix)
+		if ce == nil {
+			continue
+		}
+		ceFile := name + "-" + suffix + ".json"
+		if ce["id"] != id {
+			lint = append(lint, LintError{ceFile, fmt.Sprintf("got id %v, want event ID %q as in %s", ce["id"], id, file)})
+		}
+		if t, _ := ce["time"].(string); !timestampsEqual(t, timestamp) {
+			lint = append(lint, LintError{ceFile, fmt.Sprintf("got time %v, want timestamp %q as in %s", ce["time"], timestamp, file)})
+		}
+		if ok && ce["type"] != ceType {
+			lint = append(lint, LintError{ceFile, fmt.Sprintf("got type %v, want %q for legacy event type %q", ce["type"], ceType, eventType)})
+		}
+		if source, _ := ce["source"].(string); service != "" && !strings.HasPrefix(source, "//"+service+"/") {
+			lint = append(lint, LintError{ceFile, fmt.Sprintf("got source %v, want it to start with %q", ce["source"], "//"+service+"/")})
+		}
+		// The source only names the project for some services.
+		if source, _ := ce["source"].(string); projectOf(source) != "" && projectOf(source) != project {
+			lint = append(lint, LintError{ceFile, fmt.Sprintf("got project %q in source, want %q as in %s", projectOf(source), project, file)})
+		}
+	}
+	return lint
+}
+
+// legacyContext returns the event ID, timestamp, type, service and resource name of a legacy event,
+// whose context is either in the context property or at the root.
+func legacyContext(event map[string]interface{}) (id, timestamp, eventType, service, resource string) {
+	context, ok := event["context"].(map[string]interface{})
+	if !ok {
+		context = event
+	}
+	id, _ = getMaybeSnakeCaseField(context, "eventId").(string)
+	timestamp, _ = context["timestamp"].(string)
+	eventType, _ = getMaybeSnakeCaseField(context, "eventType").(string)
+	resource, r, ok := splitResource(context["resource"])
+	if ok && r != nil {
+		service, _ = r["service"].(string)
+	}
+	if service == "" {
+		for prefix, s := range legacyServicePrefixes {
+			if strings.HasPrefix(eventType, prefix) {
+				service = s
+			}
+		}
+	}
+	return id, timestamp, eventType, service, resource
+}
+
+// projectOf returns the project ID of a resource name or CloudEvent source, which follows
+// "projects/", or "" if it has none.
+func projectOf(name string) string {
+	_, rest, ok := strings.Cut(name, "projects/")
+	if !ok {
+		return ""
+	}
+	project, _, _ := strings.Cut(rest, "/")
+	return project
+}
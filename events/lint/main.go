@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This binary checks event test data files for problems, e.g.
+//
+//	go run ./events/lint events/data
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/GoogleCloudPlatform/functions-framework-conformance/events"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [dir ...]\n\nChecks the event test data files in each dir, events/data by default.\n", os.Args[0])
+	}
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"events/data"}
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		lint, err := events.LintEvents(dir)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		for _, l := range lint {
+			fmt.Printf("%s\n", l.Error())
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
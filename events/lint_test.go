@@ -0,0 +1,209 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintEventsCorpus(t *testing.T) {
+	lint, err := LintEvents("data")
+	if err != nil {
+		t.Fatalf("LintEvents: %v", err)
+	}
+	for _, l := range lint {
+		t.Errorf("LintEvents(): %v", l)
+	}
+}
+
+const (
+	lintLegacyEvent = `{
+  "context": {
+    "eventId": "aaaaaa-1111-bbbb-2222-cccccccccccc",
+    "timestamp": "2020-09-29T11:32:00.123Z",
+    "eventType": "google.storage.object.finalize",
+    "resource": {"service": "storage.googleapis.com", "name": "projects/_/buckets/b/objects/o", "type": "storage#object"}
+  },
+  "data": {}
+}`
+	lintCloudEvent = `{
+  "specversion": "1.0",
+  "type": "google.cloud.storage.object.v1.finalized",
+  "source": "//storage.googleapis.com/projects/_/buckets/b",
+  "subject": "objects/o",
+  "id": "aaaaaa-1111-bbbb-2222-cccccccccccc",
+  "time": "2020-09-29T11:32:00.123Z",
+  "data": {}
+}`
+)
+
+func TestLintEvents(t *testing.T) {
+	testCases := []struct {
+		name string
+		// files are added to, or replace, a complete and consistent test case named "e".
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "consistent",
+		},
+		{
+			name:  "misnamed file",
+			files: map[string]string{"e-legacy-inptu.json": `{}`},
+			want:  []string{"e-legacy-inptu.json: name does not match"},
+		},
+		{
+			name:  "invalid JSON",
+			files: map[string]string{"e-legacy-output.json": `{"data": }`},
+			want:  []string{"e-legacy-output.json: invalid JSON", "e-legacy-output.json: missing"},
+		},
+		{
+			name:  "invalid cloud event",
+			files: map[string]string{"e-cloudevent-output.json": `{"specversion": "1.0", "id": "x"}`},
+			want:  []string{"e-cloudevent-output.json: invalid cloud event", "e-cloudevent-output.json: missing"},
+		},
+		{
+			name: "invalid inputs are not checked",
+			files: map[string]string{
+				"bad-cloudevent-input-invalid.json": `{"specversion": "1.0"}`,
+				"bad-metadata.json":                 `{"description": "A bad event.", "expectedStatus": "4xx"}`,
+			},
+		},
+		{
+			name:  "invalid input without metadata",
+			files: map[string]string{"bad-cloudevent-input-invalid.json": `{"specversion": "1.0"}`},
+			want:  []string{"bad-metadata.json: missing"},
+		},
+		{
+			name: "invalid input without expected status",
+			files: map[string]string{
+				"bad-cloudevent-input-invalid.json": `{"specversion": "1.0"}`,
+				"bad-metadata.json":                 `{"description": "A bad event."}`,
+			},
+			want: []string{"bad-metadata.json: missing expectedStatus"},
+		},
+		{
+			name: "invalid input with output",
+			files: map[string]string{
+				"bad-cloudevent-input-invalid.json": `{"specversion": "1.0"}`,
+				"bad-cloudevent-output.json":        lintCloudEvent,
+				"bad-metadata.json":                 `{"description": "A bad event.", "expectedStatus": "4xx"}`,
+			},
+			want: []string{"bad-cloudevent-output.json: invalid test cases only have invalid input and metadata files"},
+		},
+		{
+			name: "unknown expected status",
+			files: map[string]string{
+				"bad-cloudevent-input-invalid.json": `{"specversion": "1.0"}`,
+				"bad-metadata.json":                 `{"description": "A bad event.", "expectedStatus": "400"}`,
+			},
+			want: []string{`bad-metadata.json: got expectedStatus "400"`},
+		},
+		{
+			name:  "expected status of valid event",
+			files: map[string]string{"e-metadata.json": `{"description": "An event.", "expectedStatus": "4xx"}`},
+			want:  []string{"e-metadata.json: expectedStatus is only for invalid test cases"},
+		},
+		{
+			name:  "metadata",
+			files: map[string]string{"e-metadata.json": `{"description": "An event.", "spec": "docs/mapping.md", "optional": true, "knownDifferences": ["nodejs"]}`},
+		},
+		{
+			name:  "metadata without description",
+			files: map[string]string{"e-metadata.json": `{"spec": "docs/mapping.md"}`},
+			want:  []string{"e-metadata.json: missing description"},
+		},
+		{
+			name:  "metadata with unknown field",
+			files: map[string]string{"e-metadata.json": `{"description": "An event.", "requried": true}`},
+			want:  []string{"e-metadata.json: invalid metadata"},
+		},
+		{
+			name:  "missing file",
+			files: map[string]string{"f-cloudevent-input.json": lintCloudEvent},
+			want:  []string{"f-legacy-input.json: missing", "f-legacy-output.json: missing", "f-cloudevent-output.json: missing"},
+		},
+		{
+			name: "CloudEvent only",
+			files: map[string]string{
+				"f-cloudevent-input.json":  lintCloudEvent,
+				"f-cloudevent-output.json": lintCloudEvent,
+			},
+		},
+		{
+			name:  "different event ID",
+			files: map[string]string{"e-cloudevent-input.json": strings.Replace(lintCloudEvent, "aaaaaa", "bbbbbb", 1)},
+			want:  []string{`e-cloudevent-input.json: got id bbbbbb-1111-bbbb-2222-cccccccccccc, want event ID`},
+		},
+		{
+			name:  "different timestamp",
+			files: map[string]string{"e-legacy-output.json": strings.Replace(lintLegacyEvent, "11:32", "11:33", 1)},
+			want:  []string{`e-legacy-output.json: got timestamp "2020-09-29T11:33:00.123Z"`},
+		},
+		{
+			name:  "unmapped type",
+			files: map[string]string{"e-cloudevent-output.json": strings.Replace(lintCloudEvent, "finalized", "deleted", 1)},
+			want:  []string{`e-cloudevent-output.json: got type google.cloud.storage.object.v1.deleted, want "google.cloud.storage.object.v1.finalized"`},
+		},
+		{
+			name:  "wrong service",
+			files: map[string]string{"e-cloudevent-output.json": strings.Replace(lintCloudEvent, "//storage", "//pubsub", 1)},
+			want:  []string{`e-cloudevent-output.json: got source //pubsub.googleapis.com/projects/_/buckets/b, want it to start with "//storage.googleapis.com/"`},
+		},
+		{
+			name:  "different project in source",
+			files: map[string]string{"e-cloudevent-output.json": strings.Replace(lintCloudEvent, "projects/_/", "projects/other-project/", 1)},
+			want:  []string{`e-cloudevent-output.json: got project "other-project" in source, want "_"`},
+		},
+		{
+			name:  "different project in resource",
+			files: map[string]string{"e-legacy-output.json": strings.Replace(lintLegacyEvent, "projects/_/", "projects/other-project/", 1)},
+			want:  []string{`e-legacy-output.json: got project "other-project", want "_"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{
+				"README.md":                "Test data.",
+				"e-legacy-input.json":      lintLegacyEvent,
+				"e-legacy-output.json":     lintLegacyEvent,
+				"e-cloudevent-input.json":  lintCloudEvent,
+				"e-cloudevent-output.json": lintCloudEvent,
+			}
+			for name, contents := range tc.files {
+				files[name] = contents
+			}
+			dir := t.TempDir()
+			for name, contents := range files {
+				writeFile(t, dir, name, contents)
+			}
+
+			lint, err := LintEvents(dir)
+			if err != nil {
+				t.Fatalf("LintEvents: %v", err)
+			}
+			if len(lint) != len(tc.want) {
+				t.Fatalf("LintEvents() = %v, want %d errors", lint, len(tc.want))
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(lint[i].Error(), want) {
+					t.Errorf("LintEvents()[%d] = %q, want it to start with %q", i, lint[i], want)
+				}
+			}
+		})
+	}
+}
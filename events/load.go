@@ -0,0 +1,216 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// dataFile describes a test data file, as encoded in its name
+// `<name>-<legacy|cloudevent>-<input|output>[-converted|-invalid].json` or
+// `<name>-<comparison|metadata>.json`.
+type dataFile struct {
+	name string
+	// eventType is "legacy" or "cloudevent", or empty for comparison policies and metadata.
+	eventType string
+	// fileType is "input", "output", "comparison" or "metadata".
+	fileType string
+	// variant is "converted", "invalid" or empty.
+	variant string
+}
+
+// parseDataFileName parses the name of a test data file. It returns false if the name does not
+// follow the naming convention of test data files.
+func parseDataFileName(fileName string) (dataFile, bool) {
+	if !strings.HasSuffix(fileName, ".json") {
+		return dataFile{}, false
+	}
+	rest := strings.TrimSuffix(fileName, ".json")
+
+	for _, ft := range []string{"comparison", "metadata"} {
+		if strings.HasSuffix(rest, "-"+ft) {
+			name := strings.TrimSuffix(rest, "-"+ft)
+			return dataFile{name: name, fileType: ft}, name != ""
+		}
+	}
+
+	var f dataFile
+	for _, v := range []string{"converted", "invalid"} {
+		if strings.HasSuffix(rest, "-"+v) {
+			f.variant = v
+			rest = strings.TrimSuffix(rest, "-"+v)
+			break
+		}
+	}
+	for _, ft := range []string{"input", "output"} {
+		if strings.HasSuffix(rest, "-"+ft) {
+			f.fileType = ft
+			rest = strings.TrimSuffix(rest, "-"+ft)
+			break
+		}
+	}
+	for _, et := range []string{"legacy", "cloudevent"} {
+		if strings.HasSuffix(rest, "-"+et) {
+			f.eventType = et
+			rest = strings.TrimSuffix(rest, "-"+et)
+			break
+		}
+	}
+	f.name = rest
+
+	switch {
+	case f.name == "", f.fileType == "", f.eventType == "":
+		return dataFile{}, false
+	case f.variant == "converted" && f.fileType != "output":
+		return dataFile{}, false
+	case f.variant == "invalid" && f.fileType != "input":
+		return dataFile{}, false
+	}
+	return f, true
+}
+
+// ReadEvents reads valid and invalid events from the test data files in dir, which follow the
+// layout of events/data. Files that are not test data files, such as READMEs, are ignored.
+func ReadEvents(dir string) (map[string]Event, map[string]InvalidEvent, error) {
+	valid, invalid, err := readEvents(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading events from %q: %v", dir, err)
+	}
+	return valid, invalid, nil
+}
+
+func readEvents(fsys fs.FS, root string) (map[string]Event, map[string]InvalidEvent, error) {
+	valid := map[string]*Event{}
+	invalid := map[string]*InvalidEvent{}
+	// metadata is added to valid or invalid events once all files are read.
+	metadata := map[string]Metadata{}
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("accessing path %q: %v", path, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, ok := parseDataFileName(d.Name())
+		if !ok {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("reading file %q: %v", path, err)
+		}
+
+		if f.variant == "invalid" {
+			ie, ok := invalid[f.name]
+			if !ok {
+				ie = &InvalidEvent{}
+				invalid[f.name] = ie
+			}
+			ie.Input.set(f.eventType, data)
+			return nil
+		}
+		if f.fileType == "metadata" {
+			var m Metadata
+			if err := json.Unmarshal(data, &m); err != nil {
+				return fmt.Errorf("unmarshalling metadata %q: %v", path, err)
+			}
+			metadata[f.name] = m
+			return nil
+		}
+
+		e, ok := valid[f.name]
+		if !ok {
+			e = &Event{}
+			valid[f.name] = e
+		}
+		switch {
+		case f.fileType == "comparison":
+			e.Comparison = data
+		case f.fileType == "input":
+			e.Input.set(f.eventType, data)
+		case f.variant == "converted":
+			e.ConvertedOutput.set(f.eventType, data)
+		default:
+			e.Output.set(f.eventType, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("walking %q: %v", root, err)
+	}
+
+	for name, m := range metadata {
+		if ie, ok := invalid[name]; ok {
+			ie.Metadata = m
+			continue
+		}
+		e, ok := valid[name]
+		if !ok {
+			e = &Event{}
+			valid[name] = e
+		}
+		e.Metadata = m
+	}
+
+	events := map[string]Event{}
+	for name, e := range valid {
+		if e.Input.LegacyEvent == nil && e.Input.CloudEvent == nil {
+			return nil, nil, fmt.Errorf("event %q has no input files", name)
+		}
+		if _, err := e.ComparisonPolicy(); err != nil {
+			return nil, nil, fmt.Errorf("event %q: %v", name, err)
+		}
+		events[name] = *e
+	}
+	invalidEvents := map[string]InvalidEvent{}
+	for name, ie := range invalid {
+		invalidEvents[name] = *ie
+	}
+	return events, invalidEvents, nil
+}
+
+// UseEvents reads the events in dir, as ReadEvents does, and validates them in addition to the
+// embedded events, or instead of them if replace is true. An event in dir replaces an embedded
+// event of the same name as a whole.
+func UseEvents(dir string, replace bool) error {
+	valid, invalid, err := ReadEvents(dir)
+	if err != nil {
+		return err
+	}
+	if replace {
+		Events = map[string]Event{}
+		InvalidEvents = map[string]InvalidEvent{}
+	}
+	for name, e := range valid {
+		Events[name] = e
+	}
+	for name, ie := range invalid {
+		InvalidEvents[name] = ie
+	}
+	return nil
+}
+
+func (ed *EventData) set(eventType string, data []byte) {
+	switch eventType {
+	case "legacy":
+		ed.LegacyEvent = data
+	case "cloudevent":
+		ed.CloudEvent = data
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDataFileName(t *testing.T) {
+	testCases := []struct {
+		fileName string
+		want     dataFile
+		ok       bool
+	}{
+		{
+			fileName: "storage-legacy-input.json",
+			want:     dataFile{name: "storage", eventType: "legacy", fileType: "input"},
+			ok:       true,
+		},
+		{
+			fileName: "firebase-auth-cloudevent-output.json",
+			want:     dataFile{name: "firebase-auth", eventType: "cloudevent", fileType: "output"},
+			ok:       true,
+		},
+		{
+			fileName: "storage_generation-legacy-output-converted.json",
+			want:     dataFile{name: "storage_generation", eventType: "legacy", fileType: "output", variant: "converted"},
+			ok:       true,
+		},
+		{
+			fileName: "invalid_time-cloudevent-input-invalid.json",
+			want:     dataFile{name: "invalid_time", eventType: "cloudevent", fileType: "input", variant: "invalid"},
+			ok:       true,
+		},
+		{
+			fileName: "storage-comparison.json",
+			want:     dataFile{name: "storage", fileType: "comparison"},
+			ok:       true,
+		},
+		{
+			fileName: "storage-metadata.json",
+			want:     dataFile{name: "storage", fileType: "metadata"},
+			ok:       true,
+		},
+		{fileName: "README.md"},
+		{fileName: "storage-input.json"},
+		{fileName: "storage-legacy.json"},
+		{fileName: "-legacy-input.json"},
+		{fileName: "storage-legacy-input-converted.json"},
+	}
+
+	for _, tc := range testCases {
+		got, ok := parseDataFileName(tc.fileName)
+		if ok != tc.ok {
+			t.Errorf("parseDataFileName(%q) ok = %v, want %v", tc.fileName, ok, tc.ok)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseDataFileName(%q) = %+v, want %+v", tc.fileName, got, tc.want)
+		}
+	}
+}
+
+// TestReadEventsMatchesEmbedded checks that reading the test data from disk yields the same
+// events as the embedded ones.
+func TestReadEventsMatchesEmbedded(t *testing.T) {
+	valid, invalid, err := ReadEvents("data")
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if diff := cmp.Diff(Events, valid); diff != "" {
+		t.Errorf("ReadEvents() valid events mismatch (-embedded +read):\n%s", diff)
+	}
+	if diff := cmp.Diff(InvalidEvents, invalid); diff != "" {
+		t.Errorf("ReadEvents() invalid events mismatch (-embedded +read):\n%s", diff)
+	}
+}
+
+func TestEventMetadata(t *testing.T) {
+	for name, e := range Events {
+		if e.Metadata.Description == "" {
+			t.Errorf("event %q has no description, add one to data/%s-metadata.json", name, name)
+		}
+	}
+	if got, want := Events["storage"].Metadata.Spec, "docs/mapping.md#cloud-storage-events"; got != want {
+		t.Errorf("Events[%q].Metadata.Spec = %q, want %q", "storage", got, want)
+	}
+	for name, e := range InvalidEvents {
+		if e.Metadata.Description == "" || e.Metadata.ExpectedStatus == "" {
+			t.Errorf("invalid event %q has no description or expected status, add them to data/%s-metadata.json", name, name)
+		}
+	}
+}
+
+func TestReadEventsWithoutInput(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "custom-legacy-output.json", `{}`)
+	if _, _, err := ReadEvents(dir); err == nil {
+		t.Errorf("ReadEvents() got nil error for an event without inputs, want error")
+	}
+}
+
+func TestUseEvents(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "custom-cloudevent-input.json", `{"specversion": "1.0"}`)
+	writeFile(t, dir, "custom-cloudevent-output.json", `{"specversion": "1.0"}`)
+	writeFile(t, dir, "custom_invalid-legacy-input-invalid.json", `{}`)
+
+	testCases := []struct {
+		name         string
+		replace      bool
+		wantEmbedded bool
+	}{
+		{
+			name:         "merge",
+			wantEmbedded: true,
+		},
+		{
+			name:    "replace",
+			replace: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			embedded, embeddedInvalid := Events, InvalidEvents
+			defer func() {
+				Events, InvalidEvents = embedded, embeddedInvalid
+			}()
+			Events, InvalidEvents = copyEvents(embedded), copyInvalidEvents(embeddedInvalid)
+
+			if err := UseEvents(dir, tc.replace); err != nil {
+				t.Fatalf("UseEvents: %v", err)
+			}
+			if _, ok := Events["custom"]; !ok {
+				t.Errorf("Events does not contain %q", "custom")
+			}
+			if _, ok := InvalidEvents["custom_invalid"]; !ok {
+				t.Errorf("InvalidEvents does not contain %q", "custom_invalid")
+			}
+			if _, ok := Events["storage"]; ok != tc.wantEmbedded {
+				t.Errorf("Events contains embedded event %q = %v, want %v", "storage", ok, tc.wantEmbedded)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func copyEvents(events map[string]Event) map[string]Event {
+	c := map[string]Event{}
+	for k, v := range events {
+		c[k] = v
+	}
+	return c
+}
+
+func copyInvalidEvents(events map[string]InvalidEvent) map[string]InvalidEvent {
+	c := map[string]InvalidEvent{}
+	for k, v := range events {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+const pubSubCloudEventType = "google.cloud.pubsub.topic.v1.messagePublished"
+
+// isPubSubLegacyType reports whether a legacy event type is a Pub/Sub message publication.
+func isPubSubLegacyType(t string) bool {
+	return t == "google.pubsub.topic.publish" || t == "providers/cloud.pubsub/eventTypes/topic.publish"
+}
+
+// validatePubSubCloudEventData checks the rules of docs/mapping.md for the data of a Pub/Sub
+// CloudEvent: the message is wrapped in a "message" property, and its "messageId" and
+// "publishTime" match the CloudEvent ID and time.
+func validatePubSubCloudEventData(name, id string, t time.Time, data map[string]interface{}) []error {
+	message, ok := data["message"].(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("expected the data of Pub/Sub event %q to be wrapped in a \"message\" object, got %s", name, compactJSON(data))}
+	}
+
+	var errs []error
+	if messageID, _ := message["messageId"].(string); messageID != id {
+		errs = append(errs, fmt.Errorf("expected \"messageId\" of Pub/Sub event %q to be the event ID %q, got %q", name, id, messageID))
+	}
+	publishTime, _ := message["publishTime"].(string)
+	if !timestampsEqual(publishTime, t.UTC().Format(time.RFC3339Nano)) {
+		errs = append(errs, fmt.Errorf("expected \"publishTime\" of Pub/Sub event %q to be the event time %s, got %q", name, t.UTC().Format(time.RFC3339Nano), publishTime))
+	}
+	return errs
+}
+
+// validatePubSubLegacyData checks that the data of a Pub/Sub legacy event is the message itself
+// rather than a CloudEvent "message" wrapper.
+func validatePubSubLegacyData(name string, data interface{}) []error {
+	d, ok := data.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("expected the data of Pub/Sub event %q to be a message object, got %s", name, compactJSON(data))}
+	}
+	if _, ok := d["message"]; ok {
+		return []error{fmt.Errorf("expected the data of Pub/Sub event %q not to be wrapped in a \"message\" object", name)}
+	}
+	return nil
+}
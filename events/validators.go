@@ -16,8 +16,10 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -30,10 +32,50 @@ type ValidationInfo struct {
 	Name          string
 	Errs          []error
 	SkippedReason string
+	// Metadata describes the validated test case, if known.
+	Metadata Metadata
+}
+
+// MarshalJSON encodes a ValidationInfo as structured data for reports. Errors that carry
+// structured diffs, such as *DiffError, are encoded as objects; other errors as their message.
+func (vi ValidationInfo) MarshalJSON() ([]byte, error) {
+	status := "passed"
+	if vi.Errs != nil {
+		status = "failed"
+	} else if vi.SkippedReason != "" {
+		status = "skipped"
+	}
+	errs := []interface{}{}
+	for _, err := range vi.Errs {
+		var de *DiffError
+		if errors.As(err, &de) {
+			errs = append(errs, de)
+		} else {
+			errs = append(errs, map[string]string{"message": err.Error()})
+		}
+	}
+	var metadata *Metadata
+	if !reflect.DeepEqual(vi.Metadata, Metadata{}) {
+		metadata = &vi.Metadata
+	}
+	return json.Marshal(struct {
+		Name          string        `json:"name"`
+		Status        string        `json:"status"`
+		SkippedReason string        `json:"skippedReason,omitempty"`
+		Errs          []interface{} `json:"errors,omitempty"`
+		Metadata      *Metadata     `json:"metadata,omitempty"`
+	}{
+		Name:          vi.Name,
+		Status:        status,
+		SkippedReason: vi.SkippedReason,
+		Errs:          errs,
+		Metadata:      metadata,
+	})
 }
 
 // PrintValidationInfos takes a list of ValidationInfos and collapses them into a single error and
-// a single log line recording which events were validation, which skipped, and why.
+// a single log line recording which events were validation, which skipped, and why. Failures of
+// optional test cases are logged, but not part of the error.
 func PrintValidationInfos(vis []*ValidationInfo) (string, error) {
 	errStr := "Validation errors:"
 	logStr := "Events tried:"
@@ -42,11 +84,24 @@ func PrintValidationInfos(vis []*ValidationInfo) (string, error) {
 	for _, vi := range vis {
 		// Collect errors into one string.
 		if vi.Errs != nil {
-			errsOccurred = true
 			viErrStr := fmt.Sprintf("%s:", vi.Name)
+			if vi.Metadata.Description != "" {
+				viErrStr = fmt.Sprintf("%s %s", viErrStr, vi.Metadata.Description)
+			}
+			if vi.Metadata.Spec != "" {
+				viErrStr = fmt.Sprintf("%s (see %s)", viErrStr, vi.Metadata.Spec)
+			}
+			if len(vi.Metadata.KnownDifferences) > 0 {
+				viErrStr = fmt.Sprintf("%s\n\t\tKnown to differ in: %s", viErrStr, strings.Join(vi.Metadata.KnownDifferences, ", "))
+			}
 			for _, err := range vi.Errs {
 				viErrStr = fmt.Sprintf("%s\n\t\t- %v", viErrStr, err)
 			}
+			if vi.Metadata.Optional {
+				logStr = fmt.Sprintf("%s\n\t- %s (FAILED, OPTIONAL):%s", logStr, vi.Name, strings.TrimPrefix(viErrStr, vi.Name+":"))
+				continue
+			}
+			errsOccurred = true
 			errStr = fmt.Sprintf("%s\n\t- %s", errStr, viErrStr)
 			logStr = fmt.Sprintf("%s\n\t- %s (FAILED)", logStr, vi.Name)
 			continue
@@ -68,12 +123,18 @@ func PrintValidationInfos(vis []*ValidationInfo) (string, error) {
 
 // ValidateEvent validates that a particular function output matches the expected contents.
 func ValidateEvent(name string, it EventType, ot EventType, got []byte) *ValidationInfo {
-	want := OutputData(name, ot, it != ot)
+	return ValidateEventData(name, Events[name], it, ot, got)
+}
+
+// ValidateEventData validates that a function output matches the expected contents of an event
+// that is not necessarily part of Events, e.g. a generated one.
+func ValidateEventData(name string, e Event, it EventType, ot EventType, got []byte) *ValidationInfo {
+	want := e.OutputData(ot, it != ot)
 
 	// If validating CloudEvent to CloudEvent (no event conversions),
 	// the output data should be exactly the same as the input data.
 	if it == CloudEvent && ot == CloudEvent {
-		want = InputData(name, it)
+		want = e.InputData(it)
 	}
 
 	if want == nil {
@@ -81,32 +142,85 @@ func ValidateEvent(name string, it EventType, ot EventType, got []byte) *Validat
 		return &ValidationInfo{
 			Name:          name,
 			SkippedReason: fmt.Sprintf("no expected output value of type %s", ot),
+			Metadata:      e.Metadata,
+		}
+	}
+
+	policy, err := e.ComparisonPolicy()
+	if err != nil {
+		return &ValidationInfo{
+			Name:     name,
+			Errs:     []error{fmt.Errorf("event %q: %v", name, err)},
+			Metadata: e.Metadata,
 		}
 	}
 
+	var vi *ValidationInfo
 	switch ot {
 	case LegacyEvent:
-		return validateLegacyEvent(name, got, want)
+		vi = validateLegacyEvent(name, got, want, policy, it != ot)
 	case CloudEvent:
-		return validateCloudEvent(name, got, want)
+		vi = validateCloudEvent(name, got, want, policy)
+	default:
+		// Should be unreachable.
+		return nil
+	}
+	vi.Metadata = e.Metadata
+	return vi
+}
+
+// ValidateInvalidEvent validates that a framework rejected a malformed or unsupported event: the
+// response must have a status of the expected status class of the event, or a 4xx or 5xx status if
+// it has none, and the function must not have been invoked.
+func ValidateInvalidEvent(name string, statusCode int, outputWritten bool) *ValidationInfo {
+	m := InvalidEvents[name].Metadata
+	vi := &ValidationInfo{
+		Name:     name,
+		Metadata: m,
+	}
+	if !statusInClass(statusCode, m.ExpectedStatus) {
+		vi.Errs = append(vi.Errs, fmt.Errorf("expected invalid event %q to be rejected with a %s status, got %d", name, statusClassName(m.ExpectedStatus), statusCode))
 	}
+	if outputWritten {
+		vi.Errs = append(vi.Errs, fmt.Errorf("expected function not to be invoked for invalid event %q, but it wrote an output file", name))
+	}
+	return vi
+}
 
-	// Should be unreachable.
-	return nil
+// statusInClass returns whether a status code is in the expected status class of an invalid event.
+func statusInClass(statusCode int, class string) bool {
+	switch class {
+	case "4xx":
+		return statusCode >= 400 && statusCode <= 499
+	case "5xx":
+		return statusCode >= 500 && statusCode <= 599
+	}
+	return statusCode >= 400 && statusCode <= 599
+}
+
+// statusClassName describes the expected status class of an invalid event.
+func statusClassName(class string) string {
+	switch class {
+	case "4xx", "5xx":
+		return class
+	}
+	return "4xx or 5xx"
 }
 
-func validateLegacyEvent(name string, gotBytes, wantBytes []byte) *ValidationInfo {
+// validateLegacyEvent validates a legacy event. If it was converted from a CloudEvent, its resource
+// may have any of the representations of docs/mapping.md, otherwise it must match exactly.
+func validateLegacyEvent(name string, gotBytes, wantBytes []byte, policy *ComparisonPolicy, isConversion bool) *ValidationInfo {
 	vi := &ValidationInfo{
 		Name: name,
 	}
 	got := make(map[string]interface{})
-	err := json.Unmarshal(gotBytes, &got)
+	err := decodeJSON(gotBytes, &got)
 	if err != nil {
 		vi.Errs = append(vi.Errs, fmt.Errorf("unmarshalling received legacy event %q: %v", name, err))
 	}
 
 	want := make(map[string]interface{})
-	err = json.Unmarshal(wantBytes, &want)
+	err = decodeJSON(wantBytes, &want)
 	if err != nil {
 		vi.Errs = append(vi.Errs, fmt.Errorf("unmarshalling expected legacy event %q: %v", name, err))
 	}
@@ -124,6 +238,10 @@ func validateLegacyEvent(name string, gotBytes, wantBytes []byte) *ValidationInf
 		name      string
 		gotValue  interface{}
 		wantValue interface{}
+		// equal overrides the default reflect.DeepEqual comparison, if set.
+		equal func(got, want interface{}) bool
+		// diff compares the values path by path instead, if set.
+		diff func(got, want interface{}) []Diff
 	}
 	gotTimestamp, err := time.Parse(time.RFC3339, gotContext["timestamp"].(string))
 	if err != nil {
@@ -135,6 +253,10 @@ func validateLegacyEvent(name string, gotBytes, wantBytes []byte) *ValidationInf
 		vi.Errs = append(vi.Errs, fmt.Errorf("parsing timestamp of expected legacy event: %v", err))
 		return vi
 	}
+	var equalResource func(got, want interface{}) bool
+	if isConversion {
+		equalResource = resourceEqual
+	}
 	fields := []eventFields{
 		{
 			name:      "ID",
@@ -155,23 +277,78 @@ func validateLegacyEvent(name string, gotBytes, wantBytes []byte) *ValidationInf
 			name:      "resource",
 			gotValue:  gotContext["resource"],
 			wantValue: wantContext["resource"],
+			equal:     equalResource,
 		},
 		{
 			name:      "data",
 			gotValue:  got["data"],
 			wantValue: want["data"],
+			diff:      policy.Diff,
 		},
 	}
 
 	for _, field := range fields {
-		if !reflect.DeepEqual(field.gotValue, field.wantValue) {
-			vi.Errs = append(vi.Errs, fmt.Errorf("unexpected %q in event %q:\ngot %+v,\nwant %+v", field.name, name, field.gotValue, field.wantValue))
+		equal := reflect.DeepEqual
+		if field.equal != nil {
+			equal = field.equal
+		}
+		diff := field.diff
+		if diff == nil {
+			diff = diffWith(equal)
 		}
+		if diffs := diff(field.gotValue, field.wantValue); len(diffs) > 0 {
+			vi.Errs = append(vi.Errs, &DiffError{Event: name, Field: field.name, Diffs: diffs, Got: field.gotValue, Want: field.wantValue})
+		}
+	}
+
+	if wantType, _ := wantContext["eventType"].(string); isPubSubLegacyType(wantType) {
+		vi.Errs = append(vi.Errs, validatePubSubLegacyData(name, got["data"])...)
 	}
 
 	return vi
 }
 
+// resourceEqual compares the legacy event context resource converted from a CloudEvent. Depending
+// on the language, frameworks represent the resource either as a string resource name or as an
+// object with "service", "name" and "type" properties (see docs/mapping.md). Either form is accepted
+// as long as the resource name matches. An object must have the expected "service" if it is known,
+// and may only omit "type".
+func resourceEqual(got, want interface{}) bool {
+	gotName, gotObj, ok := splitResource(got)
+	if !ok {
+		return false
+	}
+	wantName, wantObj, ok := splitResource(want)
+	if !ok {
+		return false
+	}
+	if gotName != wantName {
+		return false
+	}
+	if gotObj == nil {
+		return true
+	}
+	if wantService, ok := wantObj["service"]; ok && !reflect.DeepEqual(gotObj["service"], wantService) {
+		return false
+	}
+	gotType, gotOK := gotObj["type"]
+	wantType, wantOK := wantObj["type"]
+	return !gotOK || !wantOK || reflect.DeepEqual(gotType, wantType)
+}
+
+// splitResource returns the resource name of a legacy event resource and, if the resource is an
+// object, its properties.
+func splitResource(resource interface{}) (string, map[string]interface{}, bool) {
+	switch r := resource.(type) {
+	case string:
+		return r, nil, true
+	case map[string]interface{}:
+		name, ok := r["name"].(string)
+		return name, r, ok
+	}
+	return "", nil, false
+}
+
 // Some fields can present with either a CamelCase or a snake_case key. Both are acceptable.
 func getMaybeSnakeCaseField(gotContext map[string]interface{}, field string) interface{} {
 	if gotVal, ok := gotContext[field]; ok {
@@ -193,7 +370,7 @@ func getMaybeSnakeCaseField(gotContext map[string]interface{}, field string) int
 	return nil
 }
 
-func validateCloudEvent(name string, gotBytes, wantBytes []byte) *ValidationInfo {
+func validateCloudEvent(name string, gotBytes, wantBytes []byte, policy *ComparisonPolicy) *ValidationInfo {
 	vi := &ValidationInfo{
 		Name: name,
 	}
@@ -215,11 +392,19 @@ func validateCloudEvent(name string, gotBytes, wantBytes []byte) *ValidationInfo
 		return vi
 	}
 
+	gotData := unmarshalMap(got.Data(), vi)
 	fields := []struct {
 		name      string
 		gotValue  interface{}
 		wantValue interface{}
+		// diff compares the values path by path instead of cmp.Equal, if set.
+		diff func(got, want interface{}) []Diff
 	}{
+		{
+			name:      "specversion",
+			gotValue:  got.SpecVersion(),
+			wantValue: want.SpecVersion(),
+		},
 		{
 			name:      "ID",
 			gotValue:  got.ID(),
@@ -250,23 +435,53 @@ func validateCloudEvent(name string, gotBytes, wantBytes []byte) *ValidationInfo
 			gotValue:  got.DataContentType(),
 			wantValue: want.DataContentType(),
 		},
+		{
+			name:      "dataschema",
+			gotValue:  got.DataSchema(),
+			wantValue: want.DataSchema(),
+		},
+		{
+			name:      "extensions",
+			gotValue:  got.Extensions(),
+			wantValue: want.Extensions(),
+			diff:      policy.diffExtensions,
+		},
 		{
 			name:      "data",
-			gotValue:  unmarshalMap(got.Data(), vi),
+			gotValue:  gotData,
 			wantValue: unmarshalMap(want.Data(), vi),
+			diff:      policy.Diff,
 		},
 	}
 	for _, field := range fields {
-		if !cmp.Equal(field.gotValue, field.wantValue) {
-			vi.Errs = append(vi.Errs, fmt.Errorf("unexpected %q field in %q: got %v, want %v", field.name, name, field.gotValue, field.wantValue))
+		diff := field.diff
+		if diff == nil {
+			diff = diffWith(func(got, want interface{}) bool { return cmp.Equal(got, want) })
+		}
+		if diffs := diff(field.gotValue, field.wantValue); len(diffs) > 0 {
+			vi.Errs = append(vi.Errs, &DiffError{Event: name, Field: field.name, Diffs: diffs, Got: field.gotValue, Want: field.wantValue})
 		}
 	}
 
+	if want.Type() == pubSubCloudEventType {
+		vi.Errs = append(vi.Errs, validatePubSubCloudEventData(name, got.ID(), got.Time(), gotData)...)
+	}
+
 	return vi
 }
 
+// diffWith reports a whole value as changed if it is not equal according to the given function.
+func diffWith(equal func(got, want interface{}) bool) func(got, want interface{}) []Diff {
+	return func(got, want interface{}) []Diff {
+		if equal(got, want) {
+			return nil
+		}
+		return []Diff{{Kind: DiffChanged, Got: got, Want: want}}
+	}
+}
+
 func unmarshalMap(data []byte, vi *ValidationInfo) (dataMap map[string]interface{}) {
-	if err := json.Unmarshal(data, &dataMap); err != nil {
+	if err := decodeJSON(data, &dataMap); err != nil {
 		vi.Errs = append(vi.Errs, fmt.Errorf("could not parse CloudEvent data as map: %v", err))
 	}
 	return
@@ -15,7 +15,9 @@
 package events
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -43,6 +45,324 @@ func TestValidateCloudEvent(t *testing.T) {
 	}
 }
 
+func TestValidateExpectedOutputs(t *testing.T) {
+	// Every expected output must pass validation against itself, for every conversion.
+	for name := range Events {
+		for _, it := range []EventType{LegacyEvent, CloudEvent} {
+			for _, ot := range []EventType{LegacyEvent, CloudEvent} {
+				if InputData(name, it) == nil {
+					continue
+				}
+				want := OutputData(name, ot, it != ot)
+				if it == CloudEvent && ot == CloudEvent {
+					want = InputData(name, it)
+				}
+				if want == nil {
+					continue
+				}
+				if vi := ValidateEvent(name, it, ot, want); vi.Errs != nil {
+					t.Errorf("validating %q from %s to %s: %v", name, it, ot, vi.Errs)
+				}
+			}
+		}
+	}
+}
+
+func TestValidatePubSubEvents(t *testing.T) {
+	testName := "pubsub_attributes"
+	ceData := string(OutputData(testName, CloudEvent, true))
+	legacyData := string(OutputData(testName, LegacyEvent, true))
+
+	testCases := []struct {
+		name    string
+		ot      EventType
+		got     string
+		wantErr string
+	}{
+		{
+			name:    "wrong messageId",
+			ot:      CloudEvent,
+			got:     strings.Replace(ceData, `"messageId": "aaaaaa-1111-bbbb-2222-cccccccccccc"`, `"messageId": "other"`, 1),
+			wantErr: `expected "messageId" of Pub/Sub event "pubsub_attributes" to be the event ID`,
+		},
+		{
+			name:    "missing publishTime",
+			ot:      CloudEvent,
+			got:     strings.Replace(ceData, `"publishTime": "2020-09-29T11:32:00.123Z"`, `"other": "2020-09-29T11:32:00.123Z"`, 1),
+			wantErr: `expected "publishTime" of Pub/Sub event "pubsub_attributes" to be the event time`,
+		},
+		{
+			name:    "not wrapped",
+			ot:      CloudEvent,
+			got:     strings.Replace(ceData, `"message": {`, `"notmessage": {`, 1),
+			wantErr: `expected the data of Pub/Sub event "pubsub_attributes" to be wrapped in a "message" object`,
+		},
+		{
+			name:    "wrapped legacy data",
+			ot:      LegacyEvent,
+			got:     strings.Replace(legacyData, `"data": {`, `"data": {"message": {}, `, 1),
+			wantErr: `expected the data of Pub/Sub event "pubsub_attributes" not to be wrapped in a "message" object`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vi := ValidateEvent(testName, CloudEvent, tc.ot, []byte(tc.got))
+			found := false
+			for _, err := range vi.Errs {
+				if strings.Contains(err.Error(), tc.wantErr) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("ValidateEvent() errors = %v, want one containing %q", vi.Errs, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCloudEventExtensions(t *testing.T) {
+	testName := "cloudevent_extensions"
+	data := string(InputData(testName, CloudEvent))
+	if data == "" {
+		t.Fatalf("no cloudevent data")
+	}
+	traceparent := `"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",`
+	arrivalTime := `"knativearrivaltime": "2020-09-29T11:32:00.456Z",`
+
+	testCases := []struct {
+		name     string
+		got      string
+		wantErrs int
+	}{
+		{
+			name: "all extensions",
+			got:  data,
+		},
+		{
+			name: "optional extension dropped",
+			got:  strings.Replace(data, arrivalTime, "", 1),
+		},
+		{
+			name:     "required extension dropped",
+			got:      strings.Replace(data, traceparent, "", 1),
+			wantErrs: 1,
+		},
+		{
+			name:     "required extension changed",
+			got:      strings.Replace(data, traceparent, `"traceparent": "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",`, 1),
+			wantErrs: 1,
+		},
+		{
+			name: "extra extension",
+			got:  strings.Replace(data, traceparent, traceparent+`"extra": "value",`, 1),
+		},
+		{
+			name:     "dataschema dropped",
+			got:      strings.Replace(data, `"dataschema"`, `"otherschema"`, 1),
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vi := ValidateEvent(testName, CloudEvent, CloudEvent, []byte(tc.got))
+			if len(vi.Errs) != tc.wantErrs {
+				t.Errorf("ValidateEvent() got %d errors, want %d: %v", len(vi.Errs), tc.wantErrs, vi.Errs)
+			}
+		})
+	}
+}
+
+func TestResourceEqual(t *testing.T) {
+	storageObject := map[string]interface{}{
+		"service": "storage.googleapis.com",
+		"name":    "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+		"type":    "storage#object",
+	}
+	testCases := []struct {
+		name string
+		got  interface{}
+		want interface{}
+		ok   bool
+	}{
+		{
+			name: "identical objects",
+			got:  storageObject,
+			want: storageObject,
+			ok:   true,
+		},
+		{
+			name: "string for object",
+			got:  "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+			want: storageObject,
+			ok:   true,
+		},
+		{
+			name: "object for string",
+			got:  storageObject,
+			want: "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+			ok:   true,
+		},
+		{
+			name: "object without type",
+			got: map[string]interface{}{
+				"service": "storage.googleapis.com",
+				"name":    "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+			},
+			want: storageObject,
+			ok:   true,
+		},
+		{
+			name: "object without service",
+			got: map[string]interface{}{
+				"name": "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+				"type": "storage#object",
+			},
+			want: storageObject,
+		},
+		{
+			name: "different name",
+			got:  "projects/_/buckets/some-bucket/objects/folder/Other.cs",
+			want: storageObject,
+		},
+		{
+			name: "different service",
+			got: map[string]interface{}{
+				"service": "pubsub.googleapis.com",
+				"name":    "projects/_/buckets/some-bucket/objects/folder/Test.cs",
+				"type":    "storage#object",
+			},
+			want: storageObject,
+		},
+		{
+			name: "missing resource",
+			got:  nil,
+			want: storageObject,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := resourceEqual(tc.got, tc.want); got != tc.ok {
+				t.Errorf("resourceEqual(%v, %v) = %v, want %v", tc.got, tc.want, got, tc.ok)
+			}
+		})
+	}
+}
+
+func TestValidateLegacyEventResource(t *testing.T) {
+	testName := "storage_generation"
+	testCases := []struct {
+		name    string
+		it      EventType
+		wantErr bool
+	}{
+		{
+			name: "converted",
+			it:   CloudEvent,
+		},
+		{
+			name:    "not converted",
+			it:      LegacyEvent,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only a converted resource may be a string resource name instead of an object.
+			var event map[string]interface{}
+			if err := json.Unmarshal(OutputData(testName, LegacyEvent, tc.it != LegacyEvent), &event); err != nil {
+				t.Fatalf("unmarshalling expected output: %v", err)
+			}
+			context := event["context"].(map[string]interface{})
+			context["resource"] = context["resource"].(map[string]interface{})["name"]
+			got, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vi := ValidateEvent(testName, tc.it, LegacyEvent, got)
+			if gotErr := vi.Errs != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateEvent() got errors %v, want errors = %v", vi.Errs, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidEvent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		outputWritten bool
+		wantErrs      int
+	}{
+		{
+			name:       "rejected with 4xx",
+			statusCode: 400,
+		},
+		{
+			name:       "rejected with 5xx",
+			statusCode: 500,
+		},
+		{
+			name:       "accepted",
+			statusCode: 200,
+			wantErrs:   1,
+		},
+		{
+			name:          "rejected but invoked",
+			statusCode:    400,
+			outputWritten: true,
+			wantErrs:      1,
+		},
+		{
+			name:          "accepted and invoked",
+			statusCode:    204,
+			outputWritten: true,
+			wantErrs:      2,
+		},
+		{
+			name:       "redirected",
+			statusCode: 302,
+			wantErrs:   1,
+		},
+		{
+			name:       "invalid_bad_time",
+			statusCode: 400,
+		},
+		{
+			name:       "invalid_bad_time",
+			statusCode: 500,
+			wantErrs:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vi := ValidateInvalidEvent(tc.name, tc.statusCode, tc.outputWritten)
+			if len(vi.Errs) != tc.wantErrs {
+				t.Errorf("ValidateInvalidEvent(%d, %v) got %d errors, want %d: %v", tc.statusCode, tc.outputWritten, len(vi.Errs), tc.wantErrs, vi.Errs)
+			}
+		})
+	}
+}
+
+func TestInvalidEventNames(t *testing.T) {
+	for _, et := range []EventType{LegacyEvent, CloudEvent} {
+		names := InvalidEventNames(et)
+		if len(names) == 0 {
+			t.Errorf("InvalidEventNames(%s) returned no events", et)
+		}
+		for _, name := range names {
+			if _, ok := Events[name]; ok {
+				t.Errorf("invalid event %q is also a valid event", name)
+			}
+		}
+	}
+}
+
 func TestPrintValidationInfos(t *testing.T) {
 	vis := []*ValidationInfo{
 		&ValidationInfo{
@@ -110,3 +430,47 @@ func TestPrintValidationInfos(t *testing.T) {
 		t.Errorf("PrintValidationInfos error: got %v, want nil", gotErr)
 	}
 }
+
+func TestPrintValidationInfosMetadata(t *testing.T) {
+	vis := []*ValidationInfo{
+		{
+			Name: "required",
+			Errs: []error{fmt.Errorf("first error")},
+			Metadata: Metadata{
+				Description:      "A required event.",
+				Spec:             "docs/mapping.md#general-flow",
+				KnownDifferences: []string{"nodejs"},
+			},
+		},
+		{
+			Name: "optional",
+			Errs: []error{fmt.Errorf("second error")},
+			Metadata: Metadata{
+				Description: "An optional event.",
+				Optional:    true,
+			},
+		},
+	}
+
+	wantLog := `Events tried:
+	- required (FAILED)
+	- optional (FAILED, OPTIONAL): An optional event.
+		- second error`
+
+	wantErr := `Validation errors:
+	- required: A required event. (see docs/mapping.md#general-flow)
+		Known to differ in: nodejs
+		- first error`
+
+	gotLog, gotErr := PrintValidationInfos(vis)
+	if gotLog != wantLog {
+		t.Errorf("PrintValidationInfos log: got %s, want %s", gotLog, wantLog)
+	}
+	if gotErr == nil || gotErr.Error() != wantErr {
+		t.Errorf("PrintValidationInfos error: got %v, want %v", gotErr, wantErr)
+	}
+
+	if _, gotErr := PrintValidationInfos(vis[1:]); gotErr != nil {
+		t.Errorf("PrintValidationInfos error with only optional failures: got %v, want nil", gotErr)
+	}
+}